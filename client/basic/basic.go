@@ -1,6 +1,7 @@
 package basic
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"gopkg.in/resty.v1"
 
+	"github.com/binance-chain/go-sdk/common/ratelimit"
 	"github.com/binance-chain/go-sdk/types"
 	"github.com/binance-chain/go-sdk/types/tx"
 	"github.com/gorilla/websocket"
@@ -27,28 +29,83 @@ type BasicClient interface {
 	WsGet(path string, constructMsg func([]byte) (interface{}, error), closeCh <-chan struct{}) (<-chan interface{}, error)
 }
 
+// StatusError is returned for the responses whose status code is not 2xx.
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("bad response, status code %d, response: %s", e.Code, e.Body)
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (e *StatusError) StatusCode() int {
+	return e.Code
+}
+
 type client struct {
 	baseUrl string
 	apiUrl  string
+	limiter *ratelimit.RateLimiter
+}
+
+type Option func(*client)
+
+// WithRateLimiter makes the requests wait for the limiter, and honour the
+// Retry-After header of rate limited responses. Share the limiter between the
+// clients of the same API.
+func WithRateLimiter(limiter *ratelimit.RateLimiter) Option {
+	return func(c *client) {
+		c.limiter = limiter
+	}
+}
+
+func NewClient(baseUrl string, options ...Option) BasicClient {
+	c := &client{baseUrl: baseUrl, apiUrl: fmt.Sprintf("%s://%s", types.DefaultApiSchema, baseUrl+types.DefaultAPIVersionPrefix)}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+func (c *client) wait(group ratelimit.Group) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(context.Background(), group)
 }
 
-func NewClient(baseUrl string) BasicClient {
-	return &client{baseUrl: baseUrl, apiUrl: fmt.Sprintf("%s://%s", types.DefaultApiSchema, baseUrl+types.DefaultAPIVersionPrefix)}
+func (c *client) observe(group ratelimit.Group, resp *http.Response) {
+	if c.limiter != nil && resp != nil {
+		c.limiter.ObserveResponse(group, resp.StatusCode, resp.Header)
+	}
 }
 
 func (c *client) Get(path string, qp map[string]string) ([]byte, int, error) {
+	if err := c.wait(ratelimit.GroupQuery); err != nil {
+		return nil, 0, err
+	}
 	resp, err := resty.R().SetQueryParams(qp).Get(c.apiUrl + path)
 	if err != nil {
 		return nil, 0, err
 	}
+	c.observe(ratelimit.GroupQuery, resp.RawResponse)
 	if resp.StatusCode() >= http.StatusMultipleChoices || resp.StatusCode() < http.StatusOK {
-		err = fmt.Errorf("bad response, status code %d, response: %s", resp.StatusCode(), string(resp.Body()))
+		err = &StatusError{Code: resp.StatusCode(), Body: string(resp.Body())}
 	}
 	return resp.Body(), resp.StatusCode(), err
 }
 
 // Post generic method
 func (c *client) Post(path string, body interface{}, param map[string]string) ([]byte, error) {
+	group := ratelimit.GroupQuery
+	if path == "/broadcast" {
+		group = ratelimit.GroupBroadcast
+	}
+	if err := c.wait(group); err != nil {
+		return nil, err
+	}
 	resp, err := resty.R().
 		SetHeader("Content-Type", "text/plain").
 		SetBody(body).
@@ -58,8 +115,9 @@ func (c *client) Post(path string, body interface{}, param map[string]string) ([
 	if err != nil {
 		return nil, err
 	}
+	c.observe(group, resp.RawResponse)
 	if resp.StatusCode() >= http.StatusMultipleChoices {
-		err = fmt.Errorf("bad response, status code %d, response: %s", resp.StatusCode(), string(resp.Body()))
+		err = &StatusError{Code: resp.StatusCode(), Body: string(resp.Body())}
 	}
 	return resp.Body(), err
 }
@@ -105,7 +163,11 @@ func (c *client) PostTx(hexTx []byte, param map[string]string) ([]tx.TxCommitRes
 
 func (c *client) WsGet(path string, constructMsg func([]byte) (interface{}, error), closeCh <-chan struct{}) (<-chan interface{}, error) {
 	u := url.URL{Scheme: types.DefaultWSSchema, Host: c.baseUrl, Path: fmt.Sprintf("%s/%s", types.DefaultWSPrefix, path)}
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err := c.wait(ratelimit.GroupWSConnect); err != nil {
+		return nil, err
+	}
+	conn, resp, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	c.observe(ratelimit.GroupWSConnect, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1,12 +1,15 @@
 package client
 
 import (
+	"time"
+
 	"gopkg.in/resty.v1"
 
 	"github.com/binance-chain/go-sdk/client/basic"
 	"github.com/binance-chain/go-sdk/client/query"
 	"github.com/binance-chain/go-sdk/client/transaction"
 	"github.com/binance-chain/go-sdk/client/websocket"
+	"github.com/binance-chain/go-sdk/common/cache"
 	"github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/go-sdk/keys"
 )
@@ -31,9 +34,12 @@ func init() {
 	resty.DefaultClient.SetRedirectPolicy(resty.FlexibleRedirectPolicy(10))
 }
 
-func NewDexClient(baseUrl string, network types.ChainNetwork, keyManager keys.KeyManager) (DexClient, error) {
+// NewDexClient returns the client of the API at baseUrl. The basic options,
+// such as basic.WithRateLimiter, apply to the queries, the transactions and
+// the websocket streams alike.
+func NewDexClient(baseUrl string, network types.ChainNetwork, keyManager keys.KeyManager, options ...basic.Option) (DexClient, error) {
 	types.Network = network
-	c := basic.NewClient(baseUrl)
+	c := basic.NewClient(baseUrl, options...)
 	w := websocket.NewClient(c)
 	q := query.NewClient(c)
 	n, err := q.GetNodeInfo()
@@ -43,3 +49,14 @@ func NewDexClient(baseUrl string, network types.ChainNetwork, keyManager keys.Ke
 	t := transaction.NewClient(n.NodeInfo.Network, keyManager, q, c)
 	return &dexClient{BasicClient: c, QueryClient: q, TransactionClient: t, WSClient: w}, nil
 }
+
+// NewCachedDexClient returns c with GetTokens and GetMarkets answered from
+// store for ttl, see query.NewCachedClient.
+func NewCachedDexClient(c DexClient, store cache.Cache, ttl time.Duration) DexClient {
+	return &dexClient{
+		BasicClient:       c,
+		QueryClient:       query.NewCachedClient(c, store, ttl),
+		WSClient:          c,
+		TransactionClient: c,
+	}
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/binance-chain/go-sdk/common"
+	"github.com/binance-chain/go-sdk/common/cache"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// Cache key prefixes of the cached query client, to invalidate them with cache.DeletePrefix.
+const (
+	TokensCachePrefix  = "query/tokens/"
+	MarketsCachePrefix = "query/markets/"
+)
+
+type cachedClient struct {
+	QueryClient
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedClient returns a QueryClient answering GetTokens and GetMarkets
+// from store, and loading them from c when they are missing or expired.
+func NewCachedClient(c QueryClient, store cache.Cache, ttl time.Duration) QueryClient {
+	return &cachedClient{QueryClient: c, cache: store, ttl: ttl}
+}
+
+func (c *cachedClient) GetTokens(query *types.TokensQuery) ([]types.Token, error) {
+	qp, err := common.QueryParamToMap(*query)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := cache.GetOrLoad(c.cache, fmt.Sprintf("%s%v", TokensCachePrefix, qp), c.ttl, func() (interface{}, error) {
+		return c.QueryClient.GetTokens(query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the cached slice is shared, hand out a copy
+	return append([]types.Token(nil), tokens.([]types.Token)...), nil
+}
+
+func (c *cachedClient) GetMarkets(query *types.MarketsQuery) ([]types.TradingPair, error) {
+	qp, err := common.QueryParamToMap(*query)
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := cache.GetOrLoad(c.cache, fmt.Sprintf("%s%v", MarketsCachePrefix, qp), c.ttl, func() (interface{}, error) {
+		return c.QueryClient.GetMarkets(query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]types.TradingPair(nil), pairs.([]types.TradingPair)...), nil
+}
@@ -0,0 +1,47 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/binance-chain/go-sdk/common/cache"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+type countingClient struct {
+	QueryClient
+	calls int
+}
+
+func (c *countingClient) GetTokens(query *types.TokensQuery) ([]types.Token, error) {
+	c.calls++
+	return []types.Token{{Symbol: "BNB"}, {Symbol: "XYZ-000"}}, nil
+}
+
+func (c *countingClient) GetMarkets(query *types.MarketsQuery) ([]types.TradingPair, error) {
+	c.calls++
+	return []types.TradingPair{{BaseAssetSymbol: "XYZ-000", QuoteAssetSymbol: "BNB"}}, nil
+}
+
+func TestCachedClient(t *testing.T) {
+	inner := &countingClient{}
+	c := NewCachedClient(inner, cache.NewMemoryCache(), time.Minute)
+
+	tokens, err := c.GetTokens(types.NewTokensQuery())
+	assert.NoError(t, err)
+	tokens[0].Symbol = "changed"
+	tokens, err = c.GetTokens(types.NewTokensQuery())
+	assert.NoError(t, err)
+	assert.Equal(t, "BNB", tokens[0].Symbol, "callers must not modify the cached tokens")
+
+	pairs, err := c.GetMarkets(types.NewMarketsQuery())
+	assert.NoError(t, err)
+	pairs[0].BaseAssetSymbol = "changed"
+	pairs, err = c.GetMarkets(types.NewMarketsQuery())
+	assert.NoError(t, err)
+	assert.Equal(t, "XYZ-000", pairs[0].BaseAssetSymbol, "callers must not modify the cached pairs")
+
+	assert.Equal(t, 2, inner.calls)
+}
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/binance-chain/go-sdk/common"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// GetAtomicSwaps returns the atomic swaps from or to an address
+func (c *client) GetAtomicSwaps(query *types.AtomicSwapsQuery) (*types.AtomicSwaps, error) {
+	err := query.Check()
+	if err != nil {
+		return nil, err
+	}
+	qp, err := common.QueryParamToMap(*query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := c.baseClient.Get("/atomic-swaps", qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var swaps types.AtomicSwaps
+	if err := json.Unmarshal(resp, &swaps); err != nil {
+		return nil, err
+	}
+
+	return &swaps, nil
+}
+
+// GetAtomicSwap returns an atomic swap by id
+func (c *client) GetAtomicSwap(swapID string) (*types.AtomicSwap, error) {
+	if swapID == "" {
+		return nil, types.SwapIdMissingError
+	}
+
+	qp := map[string]string{}
+	resp, _, err := c.baseClient.Get("/atomic-swaps/"+swapID, qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var swap types.AtomicSwap
+	if err := json.Unmarshal(resp, &swap); err != nil {
+		return nil, err
+	}
+
+	return &swap, nil
+}
@@ -0,0 +1,32 @@
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/binance-chain/go-sdk/common"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// GetBlockExchangeFee returns the trading fees paid per block
+func (c *client) GetBlockExchangeFee(query *types.BlockExchangeFeeQuery) (*types.BlockExchangeFees, error) {
+	err := query.Check()
+	if err != nil {
+		return nil, err
+	}
+	qp, err := common.QueryParamToMap(*query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := c.baseClient.Get("/block-exchange-fee", qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var fees types.BlockExchangeFees
+	if err := json.Unmarshal(resp, &fees); err != nil {
+		return nil, err
+	}
+
+	return &fees, nil
+}
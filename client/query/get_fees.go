@@ -0,0 +1,46 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// GetFees returns the fee schedule, every item is a *types.FixedFeeParams,
+// a *types.TransferFeeParam or a *types.DexFeeParam
+func (c *client) GetFees() ([]types.FeeParam, error) {
+	qp := map[string]string{}
+	resp, _, err := c.baseClient.Get("/fees", qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(resp, &items); err != nil {
+		return nil, err
+	}
+	fees := make([]types.FeeParam, 0, len(items))
+	for _, item := range items {
+		var fee types.FeeParam
+		if _, ok := item["dex_fee_fields"]; ok {
+			fee = &types.DexFeeParam{}
+		} else if _, ok := item["fixed_fee_params"]; ok {
+			fee = &types.TransferFeeParam{}
+		} else if _, ok := item["msg_type"]; ok {
+			fee = &types.FixedFeeParams{}
+		} else {
+			return nil, fmt.Errorf("unknown fee param %v", item)
+		}
+		bz, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(bz, fee); err != nil {
+			return nil, err
+		}
+		fees = append(fees, fee)
+	}
+
+	return fees, nil
+}
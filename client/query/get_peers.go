@@ -0,0 +1,23 @@
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// GetPeers returns the network peers
+func (c *client) GetPeers() ([]types.Peer, error) {
+	qp := map[string]string{}
+	resp, _, err := c.baseClient.Get("/peers", qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []types.Peer
+	if err := json.Unmarshal(resp, &peers); err != nil {
+		return nil, err
+	}
+
+	return peers, nil
+}
@@ -0,0 +1,32 @@
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/binance-chain/go-sdk/common"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// GetTimeLocks returns the time locks of an address
+func (c *client) GetTimeLocks(query *types.TimeLocksQuery) ([]types.TimeLock, error) {
+	err := query.Check()
+	if err != nil {
+		return nil, err
+	}
+	qp, err := common.QueryParamToMap(*query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := c.baseClient.Get("/timelocks/"+query.Address, qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var locks []types.TimeLock
+	if err := json.Unmarshal(resp, &locks); err != nil {
+		return nil, err
+	}
+
+	return locks, nil
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/binance-chain/go-sdk/common"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// GetTransactions returns the transactions of an address
+func (c *client) GetTransactions(query *types.TransactionsQuery) (*types.TxPage, error) {
+	err := query.Check()
+	if err != nil {
+		return nil, err
+	}
+	qp, err := common.QueryParamToMap(*query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := c.baseClient.Get("/transactions", qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs types.TxPage
+	if err := json.Unmarshal(resp, &txs); err != nil {
+		return nil, err
+	}
+
+	return &txs, nil
+}
+
+// GetBlockTransactions returns the transactions of the block at height
+func (c *client) GetBlockTransactions(height int64) (*types.BlockTxs, error) {
+	if height <= 0 {
+		return nil, types.HeightOutOfRangeError
+	}
+
+	qp := map[string]string{}
+	resp, _, err := c.baseClient.Get(fmt.Sprintf("/transactions-in-block/%d", height), qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs types.BlockTxs
+	if err := json.Unmarshal(resp, &txs); err != nil {
+		return nil, err
+	}
+
+	return &txs, nil
+}
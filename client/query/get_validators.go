@@ -0,0 +1,23 @@
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// GetValidators returns the current validator set
+func (c *client) GetValidators() (*types.ResultValidators, error) {
+	qp := map[string]string{}
+	resp, _, err := c.baseClient.Get("/validators", qp)
+	if err != nil {
+		return nil, err
+	}
+
+	var validators types.ResultValidators
+	if err := json.Unmarshal(resp, &validators); err != nil {
+		return nil, err
+	}
+
+	return &validators, nil
+}
@@ -0,0 +1,121 @@
+package query
+
+import (
+	"context"
+
+	"github.com/binance-chain/go-sdk/common/paging"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// EachClosedOrder calls fn on every closed order matching query, fetching the
+// pages from query.Offset on until the orders or the Start/End window of the
+// query are exhausted. fn returns paging.ErrStop to end the iteration early.
+// The page size is query.Limit when set.
+func (c *client) EachClosedOrder(ctx context.Context, query *types.ClosedOrdersQuery, fn func(types.Order) error, options ...paging.Option) error {
+	if err := query.Check(); err != nil {
+		return err
+	}
+	base, pager := newQueryPager(query.Offset, query.Limit, options)
+	fetch := func(offset, limit int) (interface{}, int, int, error) {
+		q := *query
+		q.WithOffset(base + uint32(offset)).WithLimit(uint32(limit))
+		q.Total = totalOnFirstPage(offset)
+		orders, err := c.GetClosedOrders(&q)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return orders.Order, len(orders.Order), remaining(orders.Total, base, offset), nil
+	}
+	return pager.Run(ctx, fetch, func(page interface{}) error {
+		for _, order := range page.([]types.Order) {
+			if err := fn(order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EachOpenOrder calls fn on every open order matching query, see EachClosedOrder.
+func (c *client) EachOpenOrder(ctx context.Context, query *types.OpenOrdersQuery, fn func(types.Order) error, options ...paging.Option) error {
+	if err := query.Check(); err != nil {
+		return err
+	}
+	base, pager := newQueryPager(query.Offset, query.Limit, options)
+	fetch := func(offset, limit int) (interface{}, int, int, error) {
+		q := *query
+		q.WithOffset(base + uint32(offset)).WithLimit(uint32(limit))
+		q.Total = totalOnFirstPage(offset)
+		orders, err := c.GetOpenOrders(&q)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return orders.Order, len(orders.Order), remaining(orders.Total, base, offset), nil
+	}
+	return pager.Run(ctx, fetch, func(page interface{}) error {
+		for _, order := range page.([]types.Order) {
+			if err := fn(order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EachTrade calls fn on every trade matching query, see EachClosedOrder.
+func (c *client) EachTrade(ctx context.Context, query *types.TradesQuery, fn func(types.Trade) error, options ...paging.Option) error {
+	if err := query.Check(); err != nil {
+		return err
+	}
+	base, pager := newQueryPager(query.Offset, query.Limit, options)
+	fetch := func(offset, limit int) (interface{}, int, int, error) {
+		q := *query
+		q.WithOffset(base + uint32(offset)).WithLimit(uint32(limit))
+		q.Total = totalOnFirstPage(offset)
+		trades, err := c.GetTrades(&q)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return trades.Trade, len(trades.Trade), remaining(trades.Total, base, offset), nil
+	}
+	return pager.Run(ctx, fetch, func(page interface{}) error {
+		for _, trade := range page.([]types.Trade) {
+			if err := fn(trade); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func newQueryPager(offset, limit *uint32, options []paging.Option) (uint32, *paging.Pager) {
+	var base uint32
+	if offset != nil {
+		base = *offset
+	}
+	if limit != nil {
+		options = append([]paging.Option{paging.WithPageSize(int(*limit))}, options...)
+	}
+	return base, paging.NewPager(options...)
+}
+
+// totalOnFirstPage asks for the total with the first page only, it lets the
+// pager fetch the next pages concurrently.
+func totalOnFirstPage(offset int) int {
+	if offset == 0 {
+		return 1
+	}
+	return 0
+}
+
+// remaining converts the total of the API to the number of items from the
+// start offset of the iteration, -1 when the total is unknown.
+func remaining(total int, base uint32, offset int) int {
+	if offset != 0 || total < 0 {
+		return -1
+	}
+	if total < int(base) {
+		return 0
+	}
+	return total - int(base)
+}
@@ -1,7 +1,10 @@
 package query
 
 import (
+	"context"
+
 	"github.com/binance-chain/go-sdk/client/basic"
+	"github.com/binance-chain/go-sdk/common/paging"
 	"github.com/binance-chain/go-sdk/common/types"
 )
 
@@ -18,6 +21,19 @@ type QueryClient interface {
 	GetTime() (*types.Time, error)
 	GetTokens(query *types.TokensQuery) ([]types.Token, error)
 	GetNodeInfo() (*types.ResultStatus, error)
+	GetTransactions(query *types.TransactionsQuery) (*types.TxPage, error)
+	GetBlockTransactions(height int64) (*types.BlockTxs, error)
+	GetBlockExchangeFee(query *types.BlockExchangeFeeQuery) (*types.BlockExchangeFees, error)
+	GetValidators() (*types.ResultValidators, error)
+	GetPeers() ([]types.Peer, error)
+	GetFees() ([]types.FeeParam, error)
+	GetAtomicSwaps(query *types.AtomicSwapsQuery) (*types.AtomicSwaps, error)
+	GetAtomicSwap(swapID string) (*types.AtomicSwap, error)
+	GetTimeLocks(query *types.TimeLocksQuery) ([]types.TimeLock, error)
+
+	EachClosedOrder(ctx context.Context, query *types.ClosedOrdersQuery, fn func(types.Order) error, options ...paging.Option) error
+	EachOpenOrder(ctx context.Context, query *types.OpenOrdersQuery, fn func(types.Order) error, options ...paging.Option) error
+	EachTrade(ctx context.Context, query *types.TradesQuery, fn func(types.Trade) error, options ...paging.Option) error
 }
 
 type client struct {
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,6 +13,7 @@ import (
 	"github.com/tendermint/tendermint/rpc/lib/client"
 	"github.com/tendermint/tendermint/types"
 
+	"github.com/binance-chain/go-sdk/common/cache"
 	ntypes "github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/go-sdk/types/tx"
 )
@@ -27,6 +29,9 @@ type Client interface {
 	EventsClient
 	DexClient
 	OpsClient
+
+	// ChainID returns the chain ID of the node, see DiscoverChainID.
+	ChainID() (string, error)
 }
 
 type EventsClient interface {
@@ -35,18 +40,25 @@ type EventsClient interface {
 	UnsubscribeAll() error
 }
 
-func NewRPCClient(nodeURI string, network ntypes.ChainNetwork) *HTTP {
+func NewRPCClient(nodeURI string, network ntypes.ChainNetwork, options ...HTTPOption) *HTTP {
 	ntypes.Network = network
-	return NewHTTP(nodeURI, "/websocket")
+	return NewHTTP(nodeURI, "/websocket", options...)
 }
 
 type HTTP struct {
 	*WSEvents
+
+	transport   Transport
+	http        *httpTransport
+	cache       cache.Cache
+	cacheTTL    time.Duration
+	lightClient *LightClient
+	chainID     atomic.Value
 }
 
 // NewHTTP takes a remote endpoint in the form tcp://<host>:<port>
 // and the websocket path (which always seems to be "/websocket")
-func NewHTTP(remote, wsEndpoint string) *HTTP {
+func NewHTTP(remote, wsEndpoint string, options ...HTTPOption) *HTTP {
 	rc := rpcclient.NewJSONRPCClient(remote)
 	cdc := rc.Codec()
 	ctypes.RegisterAmino(cdc)
@@ -58,16 +70,63 @@ func NewHTTP(remote, wsEndpoint string) *HTTP {
 	client := &HTTP{
 		WSEvents: wsEvent,
 	}
+	for _, option := range options {
+		option(client)
+	}
+	if client.transport == TransportHTTP {
+		client.http = newHTTPTransport(cdc, remote)
+		return client
+	}
 	client.Start()
 	return client
 }
 
+// caller returns the transport of the request/response calls.
+func (c *HTTP) caller() caller {
+	if c.http != nil {
+		return c.http
+	}
+	return c.WSEvents
+}
+
+// SetTimeOut sets the timeout of the calls of both transports.
+func (c *HTTP) SetTimeOut(timeout time.Duration) {
+	c.WSEvents.SetTimeOut(timeout)
+	if c.http != nil {
+		c.http.timeout = timeout
+	}
+}
+
+// Subscribe dials the websocket first when the HTTP transport is used.
+func (c *HTTP) Subscribe(query string, outCapacity ...int) (chan ctypes.ResultEvent, error) {
+	if c.http != nil && !c.WSEvents.IsRunning() {
+		if err := c.WSEvents.Start(); err != nil && err != cmn.ErrAlreadyStarted {
+			return nil, err
+		}
+	}
+	return c.WSEvents.Subscribe(query, outCapacity...)
+}
+
+func (c *HTTP) Unsubscribe(query string) error {
+	if c.http != nil && !c.WSEvents.IsRunning() {
+		return errors.New("subscription not found")
+	}
+	return c.WSEvents.Unsubscribe(query)
+}
+
+func (c *HTTP) UnsubscribeAll() error {
+	if c.http != nil && !c.WSEvents.IsRunning() {
+		return nil
+	}
+	return c.WSEvents.UnsubscribeAll()
+}
+
 func (c *HTTP) Status() (*ctypes.ResultStatus, error) {
-	return c.WSEvents.Status()
+	return c.caller().Status()
 }
 
 func (c *HTTP) ABCIInfo() (*ctypes.ResultABCIInfo, error) {
-	return c.WSEvents.ABCIInfo()
+	return c.caller().ABCIInfo()
 }
 
 func (c *HTTP) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
@@ -81,108 +140,108 @@ func (c *HTTP) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts client.
 	if err := ValidateABCIData(data); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.ABCIQueryWithOptions(path, data, opts)
+	return c.caller().ABCIQueryWithOptions(path, data, opts)
 }
 
 func (c *HTTP) BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 	if err := ValidateTx(tx); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.BroadcastTxCommit(tx)
+	return c.caller().BroadcastTxCommit(tx)
 }
 
 func (c *HTTP) BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 	if err := ValidateTx(tx); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.BroadcastTx("broadcast_tx_async", tx)
+	return c.caller().BroadcastTx("broadcast_tx_async", tx)
 }
 
 func (c *HTTP) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 	if err := ValidateTx(tx); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.BroadcastTx("broadcast_tx_sync", tx)
+	return c.caller().BroadcastTx("broadcast_tx_sync", tx)
 }
 
 func (c *HTTP) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
 	if err := ValidateUnConfirmedTxsLimit(limit); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.UnconfirmedTxs(limit)
+	return c.caller().UnconfirmedTxs(limit)
 }
 
 func (c *HTTP) NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
-	return c.WSEvents.NumUnconfirmedTxs()
+	return c.caller().NumUnconfirmedTxs()
 }
 
 func (c *HTTP) NetInfo() (*ctypes.ResultNetInfo, error) {
-	return c.WSEvents.NetInfo()
+	return c.caller().NetInfo()
 }
 
 func (c *HTTP) DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
-	return c.WSEvents.DumpConsensusState()
+	return c.caller().DumpConsensusState()
 }
 
 func (c *HTTP) ConsensusState() (*ctypes.ResultConsensusState, error) {
-	return c.WSEvents.ConsensusState()
+	return c.caller().ConsensusState()
 }
 
 func (c *HTTP) Health() (*ctypes.ResultHealth, error) {
-	return c.WSEvents.Health()
+	return c.caller().Health()
 }
 
 func (c *HTTP) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
 	if err := ValidateHeightRange(minHeight, maxHeight); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.BlockchainInfo(minHeight, maxHeight)
+	return c.caller().BlockchainInfo(minHeight, maxHeight)
 }
 
 func (c *HTTP) Genesis() (*ctypes.ResultGenesis, error) {
-	return c.WSEvents.Genesis()
+	return c.caller().Genesis()
 }
 
 func (c *HTTP) Block(height *int64) (*ctypes.ResultBlock, error) {
 	if err := ValidateHeight(height); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.Block(height)
+	return c.caller().Block(height)
 }
 
 func (c *HTTP) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
 	if err := ValidateHeight(height); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.BlockResults(height)
+	return c.caller().BlockResults(height)
 }
 
 func (c *HTTP) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	if err := ValidateHeight(height); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.Commit(height)
+	return c.caller().Commit(height)
 }
 
 func (c *HTTP) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	if err := ValidateHash(hash); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.Tx(hash, prove)
+	return c.caller().Tx(hash, prove)
 }
 
 func (c *HTTP) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
 	if err := ValidateABCIQueryStr(query); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.TxSearch(query, prove, page, perPage)
+	return c.caller().TxSearch(query, prove, page, perPage)
 }
 
 func (c *HTTP) Validators(height *int64) (*ctypes.ResultValidators, error) {
 	if err := ValidateHeight(height); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.Validators(height)
+	return c.caller().Validators(height)
 }
 
 func (c *HTTP) QueryStore(key cmn.HexBytes, storeName string) ([]byte, error) {
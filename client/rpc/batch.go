@@ -0,0 +1,339 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/rpc/lib/types"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/common/uuid"
+)
+
+// ErrNoBatchResponse is the error of a call the node did not answer.
+var ErrNoBatchResponse = errors.New("no response to the call in the batch")
+
+// BatchCall is a call of a Batch. Err is set by Send, the result returned
+// along with the call is filled in when Err is nil.
+type BatchCall struct {
+	Method string
+	Params map[string]interface{}
+	Err    error
+
+	result interface{}
+	decode func() error
+	sent   bool
+}
+
+// Batch sends many JSON-RPC calls without waiting for each answer in turn:
+// they are pipelined on the websocket, or posted as one HTTP request, or in
+// parallel to a node that does not accept batches.
+// The calls are queued by the methods of the batch, then sent together by Send.
+type Batch struct {
+	c     *HTTP
+	calls []*BatchCall
+}
+
+// NewBatch returns an empty batch sent through the transport of the client.
+func (c *HTTP) NewBatch() *Batch {
+	return &Batch{c: c}
+}
+
+// Len returns the number of queued calls.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Call queues a call of method whose result is decoded into result.
+func (b *Batch) Call(method string, params map[string]interface{}, result interface{}) *BatchCall {
+	call := &BatchCall{Method: method, Params: params, result: result}
+	b.calls = append(b.calls, call)
+	return call
+}
+
+// invalid queues a call that is not sent because its arguments are wrong.
+func (b *Batch) invalid(method string, err error) *BatchCall {
+	call := &BatchCall{Method: method, Err: err, sent: true}
+	b.calls = append(b.calls, call)
+	return call
+}
+
+func (b *Batch) Status() (*ctypes.ResultStatus, *BatchCall) {
+	status := new(ctypes.ResultStatus)
+	return status, b.Call("status", map[string]interface{}{}, status)
+}
+
+func (b *Batch) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, *BatchCall) {
+	abciQuery := new(ctypes.ResultABCIQuery)
+	if err := ValidateABCIPath(path); err != nil {
+		return abciQuery, b.invalid("abci_query", err)
+	}
+	if err := ValidateABCIData(data); err != nil {
+		return abciQuery, b.invalid("abci_query", err)
+	}
+	return abciQuery, b.Call("abci_query", map[string]interface{}{"path": path, "data": data, "height": opts.Height, "prove": opts.Prove}, abciQuery)
+}
+
+func (b *Batch) Block(height *int64) (*ctypes.ResultBlock, *BatchCall) {
+	block := new(ctypes.ResultBlock)
+	if err := ValidateHeight(height); err != nil {
+		return block, b.invalid("block", err)
+	}
+	return block, b.Call("block", map[string]interface{}{"height": height}, block)
+}
+
+func (b *Batch) BlockResults(height *int64) (*ctypes.ResultBlockResults, *BatchCall) {
+	block := new(ctypes.ResultBlockResults)
+	if err := ValidateHeight(height); err != nil {
+		return block, b.invalid("block_results", err)
+	}
+	return block, b.Call("block_results", map[string]interface{}{"height": height}, block)
+}
+
+func (b *Batch) Commit(height *int64) (*ctypes.ResultCommit, *BatchCall) {
+	commit := new(ctypes.ResultCommit)
+	if err := ValidateHeight(height); err != nil {
+		return commit, b.invalid("commit", err)
+	}
+	return commit, b.Call("commit", map[string]interface{}{"height": height}, commit)
+}
+
+func (b *Batch) Validators(height *int64) (*ctypes.ResultValidators, *BatchCall) {
+	validators := new(ctypes.ResultValidators)
+	if err := ValidateHeight(height); err != nil {
+		return validators, b.invalid("validators", err)
+	}
+	return validators, b.Call("validators", map[string]interface{}{"height": height}, validators)
+}
+
+func (b *Batch) Tx(hash []byte, prove bool) (*ctypes.ResultTx, *BatchCall) {
+	tx := new(ctypes.ResultTx)
+	if err := ValidateHash(hash); err != nil {
+		return tx, b.invalid("tx", err)
+	}
+	return tx, b.Call("tx", map[string]interface{}{"hash": hash, "prove": prove}, tx)
+}
+
+func (b *Batch) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, *BatchCall) {
+	txs := new(ctypes.ResultTxSearch)
+	if err := ValidateABCIQueryStr(query); err != nil {
+		return txs, b.invalid("tx_search", err)
+	}
+	params := map[string]interface{}{
+		"query":    query,
+		"prove":    prove,
+		"page":     page,
+		"per_page": perPage,
+	}
+	return txs, b.Call("tx_search", params, txs)
+}
+
+// Account queues a query of the account of addr, the account is nil when it does not exist.
+func (b *Batch) Account(addr types.AccAddress) (*types.Account, *BatchCall) {
+	acc := new(types.Account)
+	result := new(ctypes.ResultABCIQuery)
+	call := b.Call("abci_query", map[string]interface{}{"path": fmt.Sprintf("/account/%s", addr.String()), "data": cmn.HexBytes(nil), "height": int64(0), "prove": false}, result)
+	call.decode = func() error {
+		resp := result.Response
+		if !resp.IsOK() {
+			return errors.New(resp.Log)
+		}
+		if len(resp.Value) == 0 {
+			return nil
+		}
+		return b.c.cdc.UnmarshalBinaryBare(resp.Value, acc)
+	}
+	return acc, call
+}
+
+// Send sends the queued calls that were not sent yet and sets their result or
+// Err. The returned error is set when the batch as a whole failed; the calls
+// answered before the failure keep their result.
+func (b *Batch) Send() error {
+	var requests []rpctypes.RPCRequest
+	pending := make(map[rpctypes.JSONRPCStringID]*BatchCall)
+	for _, call := range b.calls {
+		if call.sent {
+			continue
+		}
+		call.sent = true
+		uid, err := uuid.NewV4()
+		if err != nil {
+			call.Err = err
+			continue
+		}
+		id := rpctypes.JSONRPCStringID(uid.String())
+		request, err := rpctypes.MapToRequest(b.c.cdc, id, call.Method, call.Params)
+		if err != nil {
+			call.Err = err
+			continue
+		}
+		requests = append(requests, request)
+		pending[id] = call
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	responses, sendErr := b.c.caller().sendBatch(requests)
+	for _, resp := range responses {
+		id, ok := resp.ID.(rpctypes.JSONRPCStringID)
+		if !ok {
+			continue
+		}
+		call, ok := pending[id]
+		if !ok {
+			continue
+		}
+		delete(pending, id)
+		call.Err = call.setResult(b.c, resp)
+	}
+	for _, call := range pending {
+		call.Err = ErrNoBatchResponse
+		if sendErr != nil {
+			call.Err = sendErr
+		}
+	}
+	return sendErr
+}
+
+func (call *BatchCall) setResult(c *HTTP, resp rpctypes.RPCResponse) error {
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if err := c.cdc.UnmarshalJSON(resp.Result, call.result); err != nil {
+		return err
+	}
+	if call.decode != nil {
+		return call.decode()
+	}
+	return nil
+}
+
+// sendBatch pipelines the requests on the websocket, one frame each since
+// the node does not accept JSON arrays, and collects the responses by ID until
+// all arrived or the timeout expires.
+func (w *WSEvents) sendBatch(requests []rpctypes.RPCRequest) ([]rpctypes.RPCResponse, error) {
+	ws := w.getWsClient()
+	if !ws.IsActive() {
+		return nil, errors.New("websocket client is dialing or stopped, can't send any request")
+	}
+	outChan := make(chan rpctypes.RPCResponse, len(requests))
+	for _, request := range requests {
+		w.responseChanMap.Store(request.ID, outChan)
+	}
+	defer func() {
+		for _, request := range requests {
+			w.responseChanMap.Delete(request.ID)
+		}
+	}()
+	ctx, cancel := w.NewContext()
+	defer cancel()
+	var sendErr error
+	sent := 0
+	for _, request := range requests {
+		if sendErr = ws.Send(ctx, request); sendErr != nil {
+			break
+		}
+		sent++
+	}
+	responses := make([]rpctypes.RPCResponse, 0, sent)
+	for len(responses) < sent {
+		select {
+		case resp := <-outChan:
+			responses = append(responses, resp)
+		case <-ctx.Done():
+			return responses, ctx.Err()
+		}
+	}
+	return responses, sendErr
+}
+
+// maxParallelPosts bounds the requests in flight when a batch is sent to a
+// node without batch support.
+const maxParallelPosts = 8
+
+// sendBatch posts the requests as a JSON array. A node without batch support
+// answers the array with a single error, the requests are then posted one by
+// one in parallel, and so are the next batches.
+func (t *httpTransport) sendBatch(requests []rpctypes.RPCRequest) ([]rpctypes.RPCResponse, error) {
+	if atomic.LoadInt32(&t.noBatch) == 1 {
+		return t.postEach(requests)
+	}
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := t.post(body)
+	if err != nil {
+		return nil, err
+	}
+	if !isBatch(bz) {
+		atomic.StoreInt32(&t.noBatch, 1)
+		return t.postEach(requests)
+	}
+	var responses []rpctypes.RPCResponse
+	if err := json.Unmarshal(bz, &responses); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of the batch: %s", err.Error())
+	}
+	return responses, nil
+}
+
+// postEach posts every request on its own, it returns the responses received
+// and the first error of a request that was not answered.
+func (t *httpTransport) postEach(requests []rpctypes.RPCRequest) ([]rpctypes.RPCResponse, error) {
+	responses := make([]*rpctypes.RPCResponse, len(requests))
+	errs := make([]error, len(requests))
+	sem := make(chan struct{}, maxParallelPosts)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			body, err := json.Marshal(requests[i])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			bz, err := t.post(body)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			response := new(rpctypes.RPCResponse)
+			if err := json.Unmarshal(bz, response); err != nil {
+				errs[i] = fmt.Errorf("failed to decode the response of %s: %s", requests[i].Method, err.Error())
+				return
+			}
+			responses[i] = response
+		}(i)
+	}
+	wg.Wait()
+
+	var answered []rpctypes.RPCResponse
+	var firstErr error
+	for i, response := range responses {
+		if response != nil {
+			answered = append(answered, *response)
+		} else if firstErr == nil {
+			firstErr = errs[i]
+		}
+	}
+	return answered, firstErr
+}
+
+func isBatch(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '['
+}
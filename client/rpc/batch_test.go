@@ -0,0 +1,162 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+	ntypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+// blockHandler answers the block of every height, the lower heights last,
+// and never answers in time a call of slow.
+func blockHandler(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "block":
+		var query struct {
+			Height int64 `json:"height,string"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+		time.Sleep(time.Duration(10-query.Height) * 5 * time.Millisecond)
+		return &ctypes.ResultBlock{Block: &types.Block{Header: types.Header{Height: query.Height}}}, nil
+	case "slow":
+		time.Sleep(500 * time.Millisecond)
+		return &ctypes.ResultHealth{}, nil
+	}
+	return statusHandler(method, params)
+}
+
+// queueBlocks queues the blocks of the heights 1 to 9.
+func queueBlocks(batch *rpc.Batch) ([]*ctypes.ResultBlock, []*rpc.BatchCall) {
+	var blocks []*ctypes.ResultBlock
+	var calls []*rpc.BatchCall
+	for height := int64(1); height < 10; height++ {
+		h := height
+		block, call := batch.Block(&h)
+		blocks, calls = append(blocks, block), append(calls, call)
+	}
+	return blocks, calls
+}
+
+func assertBlocks(t *testing.T, blocks []*ctypes.ResultBlock, calls []*rpc.BatchCall) {
+	for i, call := range calls {
+		if assert.NoError(t, call.Err) {
+			assert.Equal(t, int64(i+1), blocks[i].Block.Height)
+		}
+	}
+}
+
+// sendMixedBatch sends blocks along with a status, a call the node fails and
+// a call that is not sent.
+func sendMixedBatch(t *testing.T, c *rpc.HTTP) {
+	batch := c.NewBatch()
+	blocks, calls := queueBlocks(batch)
+	status, statusCall := batch.Status()
+	failing := batch.Call("net_info", map[string]interface{}{}, new(ctypes.ResultNetInfo))
+	negative := int64(-1)
+	_, invalid := batch.Block(&negative)
+	assert.Equal(t, 12, batch.Len())
+
+	assert.NoError(t, batch.Send())
+	assertBlocks(t, blocks, calls)
+	assert.NoError(t, statusCall.Err)
+	assert.Equal(t, int64(42), status.SyncInfo.LatestBlockHeight)
+	if assert.Error(t, failing.Err) {
+		assert.Contains(t, failing.Err.Error(), "method not found")
+	}
+	assert.Error(t, invalid.Err)
+
+	// the calls already sent are not sent again
+	assert.NoError(t, batch.Send())
+}
+
+func TestBatchHTTP(t *testing.T) {
+	s := newRPCServer(blockHandler)
+	s.batches = true
+	defer s.Close()
+	c := rpc.NewRPCClient("tcp://"+s.host(), ntypes.TestNetwork, rpc.WithTransport(rpc.TransportHTTP))
+
+	sendMixedBatch(t, c)
+	posts, _ := s.counts()
+	assert.Equal(t, 1, posts, "the batch is posted as one array")
+	assert.Len(t, s.calledMethods(), 11)
+}
+
+func TestBatchHTTPWithoutBatchSupport(t *testing.T) {
+	s := newRPCServer(blockHandler)
+	defer s.Close()
+	c := rpc.NewRPCClient("tcp://"+s.host(), ntypes.TestNetwork, rpc.WithTransport(rpc.TransportHTTP))
+
+	// the array is answered with a parse error, then every call is posted
+	sendMixedBatch(t, c)
+	posts, _ := s.counts()
+	assert.Equal(t, 12, posts)
+
+	// the next batches are not sent as arrays
+	batch := c.NewBatch()
+	blocks, calls := queueBlocks(batch)
+	assert.NoError(t, batch.Send())
+	assertBlocks(t, blocks, calls)
+	posts, _ = s.counts()
+	assert.Equal(t, 21, posts)
+}
+
+func TestBatchHTTPNodeDown(t *testing.T) {
+	s := newRPCServer(blockHandler)
+	c := rpc.NewRPCClient("tcp://"+s.host(), ntypes.TestNetwork, rpc.WithTransport(rpc.TransportHTTP))
+	s.Close()
+
+	batch := c.NewBatch()
+	_, calls := queueBlocks(batch)
+	assert.Error(t, batch.Send())
+	for _, call := range calls {
+		assert.Error(t, call.Err)
+	}
+}
+
+func TestBatchWebsocket(t *testing.T) {
+	s := newRPCServer(blockHandler)
+	defer s.Close()
+	c := rpc.NewRPCClient("tcp://"+s.host(), ntypes.TestNetwork)
+	defer c.Stop()
+
+	// the calls are sent one frame each, the answers come in reverse order
+	sendMixedBatch(t, c)
+	posts, conns := s.counts()
+	assert.Equal(t, 0, posts)
+	assert.Equal(t, 1, conns)
+	assert.Len(t, s.calledMethods(), 11)
+	assert.Equal(t, 0, c.PendingRequest())
+}
+
+func TestBatchWebsocketTimeout(t *testing.T) {
+	s := newRPCServer(blockHandler)
+	defer s.Close()
+	c := rpc.NewRPCClient("tcp://"+s.host(), ntypes.TestNetwork)
+	defer c.Stop()
+	c.SetTimeOut(200 * time.Millisecond)
+
+	batch := c.NewBatch()
+	blocks, calls := queueBlocks(batch)
+	slow := batch.Call("slow", map[string]interface{}{}, new(ctypes.ResultHealth))
+	err := batch.Send()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+	assertBlocks(t, blocks, calls)
+	assert.Equal(t, err, slow.Err)
+
+	// the connection is kept, so are the subscriptions on it
+	status, err := c.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), status.SyncInfo.LatestBlockHeight)
+	_, conns := s.counts()
+	assert.Equal(t, 1, conns)
+}
@@ -0,0 +1,262 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+const (
+	defaultIteratorConcurrency   = 4
+	defaultIteratorPollPeriod    = 1 * time.Second
+	defaultIteratorStatusRetries = 5
+	maxIteratorStatusBackoff     = 30 * time.Second
+)
+
+// BlockInfo is a decoded block together with the decoded transactions and
+// their results.
+type BlockInfo struct {
+	Height     int64                    `json:"height"`
+	Hash       cmn.HexBytes             `json:"hash"`
+	Header     types.Header             `json:"header"`
+	Txs        []tx.Info                `json:"txs"`
+	BeginBlock *abci.ResponseBeginBlock `json:"begin_block,omitempty"`
+	EndBlock   *abci.ResponseEndBlock   `json:"end_block,omitempty"`
+}
+
+// FetchBlockInfo queries the block and the block results at height and
+// decodes every transaction of the block.
+func FetchBlockInfo(c Client, height int64) (*BlockInfo, error) {
+	block, err := c.Block(&height)
+	if err != nil {
+		return nil, err
+	}
+	if block.Block == nil || block.BlockMeta == nil {
+		return nil, fmt.Errorf("block %d is not available", height)
+	}
+	results, err := c.BlockResults(&height)
+	if err != nil {
+		return nil, err
+	}
+	if results.Results == nil || len(results.Results.DeliverTx) != len(block.Block.Txs) {
+		return nil, fmt.Errorf("results of block %d do not match its %d txs", height, len(block.Block.Txs))
+	}
+	txs := make([]tx.Info, 0, len(block.Block.Txs))
+	for i, txBytes := range block.Block.Txs {
+		parsedTx, err := ParseTx(tx.Cdc, txBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tx %d of block %d: %s", i, height, err.Error())
+		}
+		info := tx.Info{
+			Hash:   txBytes.Hash(),
+			Height: height,
+			Tx:     parsedTx,
+		}
+		if res := results.Results.DeliverTx[i]; res != nil {
+			info.Result = *res
+		}
+		txs = append(txs, info)
+	}
+	return &BlockInfo{
+		Height:     height,
+		Hash:       block.BlockMeta.BlockID.Hash,
+		Header:     block.Block.Header,
+		Txs:        txs,
+		BeginBlock: results.Results.BeginBlock,
+		EndBlock:   results.Results.EndBlock,
+	}, nil
+}
+
+// BlockIterator walks the chain from a start height to an end height, or keeps
+// following the tip when no end height is given. Blocks are fetched
+// concurrently but always delivered in height order.
+type BlockIterator struct {
+	client      Client
+	from        int64
+	to          int64
+	concurrency int
+	pollPeriod  time.Duration
+}
+
+type BlockIteratorOption func(*BlockIterator)
+
+// WithConcurrency sets how many blocks may be fetched at the same time.
+func WithConcurrency(concurrency int) BlockIteratorOption {
+	return func(it *BlockIterator) {
+		if concurrency > 0 {
+			it.concurrency = concurrency
+		}
+	}
+}
+
+// WithPollPeriod sets how often the node is polled for a new height once the
+// iterator has caught up with the tip.
+func WithPollPeriod(period time.Duration) BlockIteratorOption {
+	return func(it *BlockIterator) {
+		if period > 0 {
+			it.pollPeriod = period
+		}
+	}
+}
+
+// NewBlockIterator returns an iterator over the blocks in [from, to].
+// If to is less than or equal to 0, the iterator never ends and follows the tip.
+func NewBlockIterator(c Client, from, to int64, options ...BlockIteratorOption) (*BlockIterator, error) {
+	if from <= 0 {
+		return nil, fmt.Errorf("the start height should be positive")
+	}
+	if to > 0 && from > to {
+		return nil, MaxMinHeightConflictError
+	}
+	it := &BlockIterator{
+		client:      c,
+		from:        from,
+		to:          to,
+		concurrency: defaultIteratorConcurrency,
+		pollPeriod:  defaultIteratorPollPeriod,
+	}
+	for _, option := range options {
+		option(it)
+	}
+	return it, nil
+}
+
+type blockResult struct {
+	block *BlockInfo
+	err   error
+}
+
+// Run delivers every block of the range to sink, in height order. It returns
+// when the range is exhausted, ctx is done, a block can't be fetched or the
+// sink returns an error.
+func (it *BlockIterator) Run(ctx context.Context, sink BlockSink) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// pending keeps the result channels in height order, while sem bounds the
+	// number of blocks being fetched or waiting to be delivered.
+	pending := make(chan chan blockResult, it.concurrency)
+	sem := make(chan struct{}, it.concurrency)
+	dispatchErr := make(chan error, 1)
+
+	go func() {
+		defer close(pending)
+		var latest int64
+		for height := it.from; it.to <= 0 || height <= it.to; height++ {
+			var err error
+			if latest, err = it.waitForHeight(ctx, height, latest); err != nil {
+				dispatchErr <- err
+				return
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			resCh := make(chan blockResult, 1)
+			pending <- resCh
+			go func(h int64) {
+				block, err := FetchBlockInfo(it.client, h)
+				resCh <- blockResult{block, err}
+			}(height)
+		}
+	}()
+
+	for resCh := range pending {
+		var res blockResult
+		select {
+		case res = <-resCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-sem
+		if res.err != nil {
+			return res.err
+		}
+		if err := sink.Write(res.block); err != nil {
+			return err
+		}
+	}
+	select {
+	case err := <-dispatchErr:
+		return err
+	default:
+	}
+	return ctx.Err()
+}
+
+// Stream runs the iterator in background and returns the blocks through a
+// channel. The error channel receives at most one value once the iteration
+// is over, both channels are closed afterwards.
+func (it *BlockIterator) Stream(ctx context.Context) (<-chan *BlockInfo, <-chan error) {
+	out := make(chan *BlockInfo, it.concurrency)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(out)
+		err := it.Run(ctx, BlockSinkFunc(func(block *BlockInfo) error {
+			select {
+			case out <- block:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}))
+		if err != nil {
+			errCh <- err
+		}
+	}()
+	return out, errCh
+}
+
+// waitForHeight blocks until the node has committed height. latest is the
+// last known height of the node, the new one is returned.
+//
+// A failed status query is retried with an exponential backoff. When the
+// iterator follows the tip it keeps retrying until ctx is done, otherwise it
+// gives up after defaultIteratorStatusRetries consecutive failures.
+func (it *BlockIterator) waitForHeight(ctx context.Context, height, latest int64) (int64, error) {
+	failures := 0
+	for height > latest {
+		wait := it.pollPeriod
+		status, err := it.client.Status()
+		if err != nil {
+			failures++
+			if it.to > 0 && failures > defaultIteratorStatusRetries {
+				return latest, err
+			}
+			wait = statusBackoff(it.pollPeriod, failures)
+		} else {
+			failures = 0
+			latest = status.SyncInfo.LatestBlockHeight
+			if height <= latest {
+				break
+			}
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return latest, ctx.Err()
+		}
+	}
+	return latest, nil
+}
+
+// statusBackoff returns the delay before the next status query after the
+// given number of consecutive failures.
+func statusBackoff(base time.Duration, failures int) time.Duration {
+	backoff := base
+	for i := 1; i < failures && backoff < maxIteratorStatusBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxIteratorStatusBackoff {
+		backoff = maxIteratorStatusBackoff
+	}
+	return backoff
+}
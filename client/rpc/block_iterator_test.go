@@ -0,0 +1,156 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+	"github.com/binance-chain/go-sdk/client/rpc/mock"
+)
+
+// fakeChain serves empty blocks up to its height. Status fails while
+// statusErrs is positive and fetching block h sleeps for delay(h).
+type fakeChain struct {
+	mock.Client
+
+	mtx        sync.Mutex
+	height     int64
+	statusErrs int
+	delay      func(h int64) time.Duration
+
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *fakeChain) setHeight(h int64) {
+	c.mtx.Lock()
+	c.height = h
+	c.mtx.Unlock()
+}
+
+func (c *fakeChain) Status() (*ctypes.ResultStatus, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.statusErrs > 0 {
+		c.statusErrs--
+		return nil, errors.New("connection reset")
+	}
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: c.height}}, nil
+}
+
+func (c *fakeChain) Block(height *int64) (*ctypes.ResultBlock, error) {
+	c.mtx.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mtx.Unlock()
+	defer func() {
+		c.mtx.Lock()
+		c.inFlight--
+		c.mtx.Unlock()
+	}()
+	if c.delay != nil {
+		time.Sleep(c.delay(*height))
+	}
+	block := &types.Block{Header: types.Header{Height: *height}}
+	return &ctypes.ResultBlock{
+		Block:     block,
+		BlockMeta: &types.BlockMeta{Header: block.Header},
+	}, nil
+}
+
+func (c *fakeChain) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+	return &ctypes.ResultBlockResults{Height: *height, Results: &state.ABCIResponses{}}, nil
+}
+
+func collectHeights(heights *[]int64) rpc.BlockSink {
+	return rpc.BlockSinkFunc(func(block *rpc.BlockInfo) error {
+		*heights = append(*heights, block.Height)
+		return nil
+	})
+}
+
+func TestBlockIteratorOrder(t *testing.T) {
+	// Later blocks are fetched faster so that they complete out of order.
+	chain := &fakeChain{height: 20, delay: func(h int64) time.Duration {
+		return time.Duration(21-h) * time.Millisecond
+	}}
+	it, err := rpc.NewBlockIterator(chain, 3, 17, rpc.WithConcurrency(4))
+	assert.NoError(t, err)
+
+	var heights []int64
+	assert.NoError(t, it.Run(context.Background(), collectHeights(&heights)))
+	assert.Len(t, heights, 15)
+	for i, h := range heights {
+		assert.Equal(t, int64(3+i), h)
+	}
+	assert.True(t, chain.maxInFlight <= 4, "at most 4 blocks fetched at once, got %d", chain.maxInFlight)
+	assert.True(t, chain.maxInFlight > 1, "blocks should be fetched concurrently")
+}
+
+func TestBlockIteratorInvalidRange(t *testing.T) {
+	chain := &fakeChain{height: 10}
+	_, err := rpc.NewBlockIterator(chain, 0, 5)
+	assert.Error(t, err)
+	_, err = rpc.NewBlockIterator(chain, 6, 5)
+	assert.Equal(t, rpc.MaxMinHeightConflictError, err)
+}
+
+func TestBlockIteratorSinkError(t *testing.T) {
+	chain := &fakeChain{height: 100}
+	it, err := rpc.NewBlockIterator(chain, 1, 100, rpc.WithConcurrency(8))
+	assert.NoError(t, err)
+
+	sinkErr := errors.New("disk full")
+	var heights []int64
+	err = it.Run(context.Background(), rpc.BlockSinkFunc(func(block *rpc.BlockInfo) error {
+		if block.Height == 5 {
+			return sinkErr
+		}
+		heights = append(heights, block.Height)
+		return nil
+	}))
+	assert.Equal(t, sinkErr, err)
+	assert.Equal(t, []int64{1, 2, 3, 4}, heights)
+}
+
+func TestBlockIteratorFollowRetriesStatus(t *testing.T) {
+	chain := &fakeChain{height: 2, statusErrs: 3}
+	it, err := rpc.NewBlockIterator(chain, 1, 0, rpc.WithPollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	blocks, errCh := it.Stream(ctx)
+	for h := int64(1); h <= 4; h++ {
+		if h == 3 {
+			chain.setHeight(4)
+		}
+		select {
+		case block := <-blocks:
+			assert.Equal(t, h, block.Height)
+		case err := <-errCh:
+			t.Fatalf("iteration stopped at height %d: %v", h, err)
+		}
+	}
+	cancel()
+	for range blocks {
+	}
+	assert.Equal(t, context.Canceled, <-errCh)
+}
+
+func TestBlockIteratorRangeStatusError(t *testing.T) {
+	chain := &fakeChain{height: 10, statusErrs: 100}
+	it, err := rpc.NewBlockIterator(chain, 1, 5, rpc.WithPollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+	assert.EqualError(t, it.Run(context.Background(), collectHeights(new([]int64))), "connection reset")
+}
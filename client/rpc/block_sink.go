@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// BlockSink receives the blocks produced by a BlockIterator, in height order.
+// Returning an error stops the iteration.
+type BlockSink interface {
+	Write(block *BlockInfo) error
+}
+
+// BlockSinkFunc adapts a callback to a BlockSink.
+type BlockSinkFunc func(block *BlockInfo) error
+
+func (f BlockSinkFunc) Write(block *BlockInfo) error {
+	return f(block)
+}
+
+type jsonLinesSink struct {
+	mtx sync.Mutex
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLinesSink returns a sink writing every block as one JSON document per line.
+func NewJSONLinesSink(w io.Writer) BlockSink {
+	bw := bufio.NewWriter(w)
+	return &jsonLinesSink{w: bw, enc: json.NewEncoder(bw)}
+}
+
+func (s *jsonLinesSink) Write(block *BlockInfo) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if err := s.enc.Encode(block); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+type multiSink []BlockSink
+
+// NewMultiSink returns a sink that hands every block to all the given sinks, in order.
+func NewMultiSink(sinks ...BlockSink) BlockSink {
+	return multiSink(sinks)
+}
+
+func (m multiSink) Write(block *BlockInfo) error {
+	for _, s := range m {
+		if err := s.Write(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
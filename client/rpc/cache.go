@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/common/cache"
+	"github.com/binance-chain/go-sdk/types/msg"
+)
+
+// Cache key prefixes of the RPC client, to invalidate them with cache.DeletePrefix.
+const (
+	RPCCachePrefix          = "rpc/"
+	TokenInfoCachePrefix    = RPCCachePrefix + "tokens/info/"
+	TradingPairsCachePrefix = RPCCachePrefix + "dex/pairs/"
+
+	feesCacheKey = RPCCachePrefix + "param/fees"
+
+	cacheRefreshQuery = "tm.event = 'Tx'"
+)
+
+// SetCache makes GetTokenInfo, GetTradingPairs, GetFee and the token check of
+// GetBalance read through store, keeping the answers for ttl. A nil store
+// disables the cache. Set it before the client is shared.
+func (c *HTTP) SetCache(store cache.Cache, ttl time.Duration) {
+	c.cache = store
+	c.cacheTTL = ttl
+}
+
+// InvalidateCache drops every answer cached by the client.
+func (c *HTTP) InvalidateCache() {
+	if c.cache != nil {
+		c.cache.DeletePrefix(RPCCachePrefix)
+	}
+}
+
+// RefreshCacheOnEvents subscribes to the committed transactions and drops the
+// cached tokens when a token is issued, minted or burnt, and the cached
+// trading pairs when a pair is listed. The fees only expire with the TTL.
+func (c *HTTP) RefreshCacheOnEvents() (stop func() error, err error) {
+	out, err := c.Subscribe(cacheRefreshQuery, 100)
+	if err != nil {
+		return nil, err
+	}
+	quit := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case event := <-out:
+				if data, ok := event.Data.(types.EventDataTx); ok {
+					c.invalidateForTx(data.Tx)
+				}
+			case <-quit:
+				return
+			}
+		}
+	}()
+	return func() error {
+		close(quit)
+		return c.Unsubscribe(cacheRefreshQuery)
+	}, nil
+}
+
+func (c *HTTP) invalidateForTx(txBytes []byte) {
+	if c.cache == nil {
+		return
+	}
+	parsedTx, err := ParseTx(c.cdc, txBytes)
+	if err != nil {
+		// the change is unknown, be safe
+		c.InvalidateCache()
+		return
+	}
+	for _, m := range parsedTx.GetMsgs() {
+		switch m.Type() {
+		case msg.TokenIssueMsg{}.Type(), msg.MintMsg{}.Type(), msg.TokenBurnMsg{}.Type():
+			c.cache.DeletePrefix(TokenInfoCachePrefix)
+		case msg.DexListMsg{}.Type():
+			c.cache.DeletePrefix(TradingPairsCachePrefix)
+		}
+	}
+}
+
+func (c *HTTP) cached(key string, load func() (interface{}, error)) (interface{}, error) {
+	return cache.GetOrLoad(c.cache, key, c.cacheTTL, load)
+}
+
+func tokenInfoCacheKey(symbol string) string {
+	return TokenInfoCachePrefix + symbol
+}
+
+func tradingPairsCacheKey(offset, limit int) string {
+	return fmt.Sprintf("%s%d/%d", TradingPairsCachePrefix, offset, limit)
+}
@@ -0,0 +1,32 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/rpc/client"
+)
+
+// DiscoverChainID returns the chain ID of the node, the network of its node info.
+func DiscoverChainID(c client.StatusClient) (string, error) {
+	status, err := c.Status()
+	if err != nil {
+		return "", err
+	}
+	if status.NodeInfo.Network == "" {
+		return "", fmt.Errorf("the node did not report its chain id")
+	}
+	return status.NodeInfo.Network, nil
+}
+
+// ChainID returns the chain ID of the node, discovered by the first call.
+func (c *HTTP) ChainID() (string, error) {
+	if chainID, ok := c.chainID.Load().(string); ok {
+		return chainID, nil
+	}
+	chainID, err := DiscoverChainID(c)
+	if err != nil {
+		return "", err
+	}
+	c.chainID.Store(chainID)
+	return chainID, nil
+}
@@ -1,8 +1,10 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/binance-chain/go-sdk/common/paging"
 	"github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/go-sdk/types/tx"
 )
@@ -10,6 +12,7 @@ import (
 const (
 	AccountStoreName = "acc"
 	TokenStoreName   = "tokens"
+	PairStoreName    = "pairs"
 	ParamABCIPrefix  = "param"
 	TimeLockMsgRoute = "timelock"
 )
@@ -20,6 +23,8 @@ type DexClient interface {
 	GetTokenInfo(symbol string) (*types.Token, error)
 	GetAccount(addr types.AccAddress) (acc types.Account, err error)
 	GetCommitAccount(addr types.AccAddress) (acc types.Account, err error)
+	GetVerifiedCommitAccount(addr types.AccAddress) (acc types.Account, err error)
+	GetVerifiedTokenInfo(symbol string) (*types.Token, error)
 
 	GetBalances(addr types.AccAddress) ([]types.TokenBalance, error)
 	GetBalance(addr types.AccAddress, symbol string) (*types.TokenBalance, error)
@@ -31,13 +36,21 @@ type DexClient interface {
 	GetProposal(proposalId int64) (types.Proposal, error)
 	GetTimelocks(address string) ([]types.TimeLockRecord, error)
 	GetTimelock(address string, recordID int64) (types.TimeLockRecord, error)
+
+	GetAccountAtHeight(addr types.AccAddress, height int64) (types.Account, error)
+	GetBalancesAtHeight(addr types.AccAddress, height int64) ([]types.TokenBalance, error)
+	GetTokenInfoAtHeight(symbol string, height int64) (*types.Token, error)
+	GetTradingPairsAtHeight(offset int, limit int, height int64) ([]types.TradingPair, error)
+
+	EachToken(ctx context.Context, fn func(types.Token) error, options ...paging.Option) error
+	EachTradingPair(ctx context.Context, fn func(types.TradingPair) error, options ...paging.Option) error
 }
 
 func (c *HTTP) TxInfoSearch(query string, prove bool, page, perPage int) ([]tx.Info, error) {
 	if err := ValidateTxSearchQueryStr(query); err != nil {
 		return nil, err
 	}
-	return c.WSEvents.TxInfoSearch(query, prove, page, perPage)
+	return c.caller().TxInfoSearch(query, prove, page, perPage)
 }
 
 func (c *HTTP) ListAllTokens(offset int, limit int) ([]types.Token, error) {
@@ -62,6 +75,29 @@ func (c *HTTP) GetTokenInfo(symbol string) (*types.Token, error) {
 	if err := ValidateSymbol(symbol); err != nil {
 		return nil, err
 	}
+	cached, err := c.cached(tokenInfoCacheKey(symbol), func() (interface{}, error) {
+		return c.getTokenInfo(symbol, 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+	token := *cached.(*types.Token)
+	return &token, nil
+}
+
+func (c *HTTP) getTokenInfo(symbol string, height int64) (*types.Token, error) {
+	if height != 0 {
+		bz, err := c.queryStoreAtHeight(tokenStoreKey(symbol), TokenStoreName, "key", height)
+		if err != nil {
+			return nil, err
+		}
+		if bz == nil {
+			return nil, fmt.Errorf("token %s not found at height %d", symbol, height)
+		}
+		token := new(types.Token)
+		err = c.cdc.UnmarshalBinaryBare(bz, token)
+		return token, err
+	}
 	path := fmt.Sprintf("tokens/info/%s", symbol)
 	result, err := c.ABCIQuery(path, nil)
 	if err != nil {
@@ -105,15 +141,27 @@ func (c *HTTP) GetCommitAccount(addr types.AccAddress) (acc types.Account, err e
 // 2. Node receive Tx(AccountA --> AccountB 2BNB) and check have passed, but not included in block yet.
 // 3. GetAccount will return AccountA(Balance: 8BNB, sequence: 2), AccountB(Balance: 7BNB, sequence: 1)
 func (c *HTTP) GetAccount(addr types.AccAddress) (acc types.Account, err error) {
-	result, err := c.ABCIQuery(fmt.Sprintf("/account/%s", addr.String()), nil)
-	if err != nil {
-		return nil, err
-	}
-	resp := result.Response
-	if !resp.IsOK() {
-		return nil, errors.New(resp.Log)
+	return c.getAccount(addr, 0)
+}
+
+func (c *HTTP) getAccount(addr types.AccAddress, height int64) (acc types.Account, err error) {
+	var value []byte
+	if height != 0 {
+		value, err = c.queryStoreAtHeight(accountStoreKey(addr), AccountStoreName, "key", height)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		result, err := c.ABCIQuery(fmt.Sprintf("/account/%s", addr.String()), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp := result.Response
+		if !resp.IsOK() {
+			return nil, errors.New(resp.Log)
+		}
+		value = resp.GetValue()
 	}
-	value := result.Response.GetValue()
 	if len(value) == 0 {
 		return nil, nil
 	}
@@ -125,7 +173,11 @@ func (c *HTTP) GetAccount(addr types.AccAddress) (acc types.Account, err error)
 }
 
 func (c *HTTP) GetBalances(addr types.AccAddress) ([]types.TokenBalance, error) {
-	account, err := c.GetAccount(addr)
+	return c.getBalances(addr, 0)
+}
+
+func (c *HTTP) getBalances(addr types.AccAddress, height int64) ([]types.TokenBalance, error) {
+	account, err := c.getAccount(addr, height)
 	if err != nil {
 		return nil, err
 	}
@@ -179,13 +231,20 @@ func (c *HTTP) GetBalance(addr types.AccAddress, symbol string) (*types.TokenBal
 }
 
 func (c *HTTP) GetFee() ([]types.FeeParam, error) {
-	rawFee, err := c.ABCIQuery(fmt.Sprintf("%s/fees", ParamABCIPrefix), nil)
+	fees, err := c.cached(feesCacheKey, func() (interface{}, error) {
+		rawFee, err := c.ABCIQuery(fmt.Sprintf("%s/fees", ParamABCIPrefix), nil)
+		if err != nil {
+			return nil, err
+		}
+		var fees []types.FeeParam
+		err = c.cdc.UnmarshalBinaryLengthPrefixed(rawFee.Response.GetValue(), &fees)
+		return fees, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	var fees []types.FeeParam
-	err = c.cdc.UnmarshalBinaryLengthPrefixed(rawFee.Response.GetValue(), &fees)
-	return fees, err
+	// the cached slice is shared, hand out a copy
+	return append([]types.FeeParam(nil), fees.([]types.FeeParam)...), nil
 }
 
 func (c *HTTP) GetOpenOrders(addr types.AccAddress, pair string) ([]types.OpenOrder, error) {
@@ -215,16 +274,23 @@ func (c *HTTP) GetTradingPairs(offset int, limit int) ([]types.TradingPair, erro
 	if err := ValidateOffset(offset); err != nil {
 		return nil, err
 	}
-	rawTradePairs, err := c.ABCIQuery(fmt.Sprintf("dex/pairs/%d/%d", offset, limit), nil)
+	pairs, err := c.cached(tradingPairsCacheKey(offset, limit), func() (interface{}, error) {
+		rawTradePairs, err := c.ABCIQuery(fmt.Sprintf("dex/pairs/%d/%d", offset, limit), nil)
+		if err != nil {
+			return nil, err
+		}
+		pairs := make([]types.TradingPair, 0)
+		if rawTradePairs.Response.GetValue() == nil {
+			return pairs, nil
+		}
+		err = c.cdc.UnmarshalBinaryLengthPrefixed(rawTradePairs.Response.GetValue(), &pairs)
+		return pairs, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	pairs := make([]types.TradingPair, 0)
-	if rawTradePairs.Response.GetValue() == nil {
-		return pairs, nil
-	}
-	err = c.cdc.UnmarshalBinaryLengthPrefixed(rawTradePairs.Response.GetValue(), &pairs)
-	return pairs, err
+	// the cached slice is shared, hand out a copy
+	return append([]types.TradingPair(nil), pairs.([]types.TradingPair)...), nil
 }
 
 func (c *HTTP) GetDepth(tradePair string, level int) (*types.OrderBook, error) {
@@ -356,6 +422,11 @@ func (c *HTTP) GetProposal(proposalId int64) (types.Proposal, error) {
 }
 
 func (c *HTTP) existsCC(symbol string) bool {
+	if c.cache != nil {
+		if _, ok := c.cache.Get(tokenInfoCacheKey(symbol)); ok {
+			return true
+		}
+	}
 	resp, err := c.ABCIQuery(fmt.Sprintf("tokens/info/%s", symbol), nil)
 	if err != nil {
 		return false
@@ -371,5 +442,9 @@ func (c *HTTP) existsCC(symbol string) bool {
 	if err != nil {
 		return false
 	}
+	// only found tokens are cached, a missing one may be issued at any time
+	if c.cache != nil {
+		c.cache.Set(tokenInfoCacheKey(symbol), &token, c.cacheTTL)
+	}
 	return true
 }
@@ -0,0 +1,125 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// ErrHeightPruned is returned, wrapped, when the node no longer keeps the
+// state at the requested height. Query an archive node instead.
+var ErrHeightPruned = errors.New("the state at this height is pruned by the node")
+
+// pruned state messages of the iavl store
+var prunedStateMessages = []string{"pruned", "version does not exist"}
+
+// GetAccountAtHeight returns the account as it was after the block at height.
+func (c *HTTP) GetAccountAtHeight(addr types.AccAddress, height int64) (types.Account, error) {
+	if err := ValidatePinnedHeight(height); err != nil {
+		return nil, err
+	}
+	return c.getAccount(addr, height)
+}
+
+// GetBalancesAtHeight returns the balances as they were after the block at height.
+func (c *HTTP) GetBalancesAtHeight(addr types.AccAddress, height int64) ([]types.TokenBalance, error) {
+	if err := ValidatePinnedHeight(height); err != nil {
+		return nil, err
+	}
+	return c.getBalances(addr, height)
+}
+
+// GetTokenInfoAtHeight returns the token as it was after the block at height.
+func (c *HTTP) GetTokenInfoAtHeight(symbol string, height int64) (*types.Token, error) {
+	if err := ValidateSymbol(symbol); err != nil {
+		return nil, err
+	}
+	if err := ValidatePinnedHeight(height); err != nil {
+		return nil, err
+	}
+	return c.getTokenInfo(symbol, height)
+}
+
+// GetTradingPairsAtHeight returns the trading pairs as they were after the
+// block at height, read from the pairs store in the order GetTradingPairs lists
+// them.
+func (c *HTTP) GetTradingPairsAtHeight(offset int, limit int, height int64) ([]types.TradingPair, error) {
+	if err := ValidateLimit(limit); err != nil {
+		return nil, err
+	}
+	if err := ValidateOffset(offset); err != nil {
+		return nil, err
+	}
+	if err := ValidatePinnedHeight(height); err != nil {
+		return nil, err
+	}
+	bz, err := c.queryStoreAtHeight(nil, PairStoreName, "subspace", height)
+	if err != nil {
+		return nil, err
+	}
+	var kvs []cmn.KVPair
+	if len(bz) != 0 {
+		if err := c.cdc.UnmarshalBinaryLengthPrefixed(bz, &kvs); err != nil {
+			return nil, err
+		}
+	}
+	pairs := make([]types.TradingPair, 0)
+	for _, kv := range kvs {
+		// the store keeps the recent prices next to the pairs, keyed BASE_QUOTE
+		if !strings.Contains(string(kv.Key), "_") {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(pairs) == limit {
+			break
+		}
+		var pair types.TradingPair
+		if err := c.cdc.UnmarshalBinaryBare(kv.Value, &pair); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// queryStoreAtHeight runs the key or subspace query of the store on the state
+// after the block at height. The answer must come from that height.
+func (c *HTTP) queryStoreAtHeight(key cmn.HexBytes, storeName string, query string, height int64) ([]byte, error) {
+	path := fmt.Sprintf("/store/%s/%s", storeName, query)
+	result, err := c.ABCIQueryWithOptions(path, key, client.ABCIQueryOptions{Height: height})
+	if err != nil {
+		if isPrunedState(err.Error()) {
+			return nil, fmt.Errorf("%w: height %d: %s", ErrHeightPruned, height, err.Error())
+		}
+		return nil, err
+	}
+	resp := result.Response
+	if !resp.IsOK() {
+		if isPrunedState(resp.Log) {
+			return nil, fmt.Errorf("%w: height %d: %s", ErrHeightPruned, height, resp.Log)
+		}
+		return nil, errors.New(resp.Log)
+	}
+	if resp.Height != height {
+		return nil, fmt.Errorf("the node answered with the state at height %d instead of %d", resp.Height, height)
+	}
+	return resp.Value, nil
+}
+
+func isPrunedState(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, pruned := range prunedStateMessages {
+		if strings.Contains(msg, pruned) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,283 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/tendermint/go-amino"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/rpc/lib/types"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/common/uuid"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+// Transport selects how the request/response calls of the RPC client reach the node.
+type Transport int
+
+const (
+	// TransportWebsocket sends every call over the persistent websocket.
+	TransportWebsocket Transport = iota
+	// TransportHTTP sends every call as a stateless HTTP POST, the websocket is
+	// only dialed by the first subscription.
+	TransportHTTP
+)
+
+type HTTPOption func(*HTTP)
+
+// WithTransport sets the transport of the request/response calls, TransportWebsocket by default.
+func WithTransport(transport Transport) HTTPOption {
+	return func(c *HTTP) {
+		c.transport = transport
+	}
+}
+
+// caller is the request/response part of the RPC API, implemented by both transports.
+type caller interface {
+	Status() (*ctypes.ResultStatus, error)
+	ABCIInfo() (*ctypes.ResultABCIInfo, error)
+	ABCIQueryWithOptions(path string, data cmn.HexBytes, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error)
+	BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error)
+	BroadcastTx(route string, tx types.Tx) (*ctypes.ResultBroadcastTx, error)
+	UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error)
+	NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error)
+	NetInfo() (*ctypes.ResultNetInfo, error)
+	DumpConsensusState() (*ctypes.ResultDumpConsensusState, error)
+	ConsensusState() (*ctypes.ResultConsensusState, error)
+	Health() (*ctypes.ResultHealth, error)
+	BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error)
+	Genesis() (*ctypes.ResultGenesis, error)
+	Block(height *int64) (*ctypes.ResultBlock, error)
+	BlockResults(height *int64) (*ctypes.ResultBlockResults, error)
+	Commit(height *int64) (*ctypes.ResultCommit, error)
+	Tx(hash []byte, prove bool) (*ctypes.ResultTx, error)
+	TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error)
+	TxInfoSearch(query string, prove bool, page, perPage int) ([]tx.Info, error)
+	Validators(height *int64) (*ctypes.ResultValidators, error)
+
+	sendBatch(requests []rpctypes.RPCRequest) ([]rpctypes.RPCResponse, error)
+}
+
+var (
+	_ caller = (*WSEvents)(nil)
+	_ caller = (*httpTransport)(nil)
+)
+
+// httpTransport sends the JSON-RPC calls as HTTP POST requests.
+type httpTransport struct {
+	cdc     *amino.Codec
+	address string
+	client  *http.Client
+	timeout time.Duration
+	noBatch int32 // set once the node answered a batch with a single error
+}
+
+func newHTTPTransport(cdc *amino.Codec, remote string) *httpTransport {
+	protocol, address, dialer := makeHTTPDialer(remote)
+	// a websocket address names the same server over HTTP
+	switch protocol {
+	case protoWS:
+		protocol = protoHTTP
+	case protoWSS:
+		protocol = protoHTTPS
+	}
+	return &httpTransport{
+		cdc:     cdc,
+		address: protocol + "://" + address,
+		client: &http.Client{
+			Transport: &http.Transport{
+				// Set to true to prevent GZIP-bomb DoS attacks
+				DisableCompression: true,
+				Dial:               dialer,
+			},
+		},
+		timeout: defaultTimeout,
+	}
+}
+
+// post sends body and returns the body of the answer.
+func (t *httpTransport) post(body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, t.address, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && len(bz) == 0 {
+		return nil, fmt.Errorf("rpc node answered with status %d", resp.StatusCode)
+	}
+	return bz, nil
+}
+
+func (t *httpTransport) call(method string, params map[string]interface{}, result interface{}) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	request, err := rpctypes.MapToRequest(t.cdc, rpctypes.JSONRPCStringID(id.String()), method, params)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	bz, err := t.post(body)
+	if err != nil {
+		return err
+	}
+	var response rpctypes.RPCResponse
+	if err := json.Unmarshal(bz, &response); err != nil {
+		return fmt.Errorf("failed to decode the response of %s: %s", method, err.Error())
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	return t.cdc.UnmarshalJSON(response.Result, result)
+}
+
+func (t *httpTransport) Status() (*ctypes.ResultStatus, error) {
+	status := new(ctypes.ResultStatus)
+	err := t.call("status", map[string]interface{}{}, status)
+	return status, err
+}
+
+func (t *httpTransport) ABCIInfo() (*ctypes.ResultABCIInfo, error) {
+	info := new(ctypes.ResultABCIInfo)
+	err := t.call("abci_info", map[string]interface{}{}, info)
+	return info, err
+}
+
+func (t *httpTransport) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	abciQuery := new(ctypes.ResultABCIQuery)
+	err := t.call("abci_query", map[string]interface{}{"path": path, "data": data, "height": opts.Height, "prove": opts.Prove}, abciQuery)
+	return abciQuery, err
+}
+
+func (t *httpTransport) BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	txCommit := new(ctypes.ResultBroadcastTxCommit)
+	err := t.call("broadcast_tx_commit", map[string]interface{}{"tx": tx}, txCommit)
+	return txCommit, err
+}
+
+func (t *httpTransport) BroadcastTx(route string, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	txRes := new(ctypes.ResultBroadcastTx)
+	err := t.call(route, map[string]interface{}{"tx": tx}, txRes)
+	return txRes, err
+}
+
+func (t *httpTransport) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+	unConfirmTxs := new(ctypes.ResultUnconfirmedTxs)
+	err := t.call("unconfirmed_txs", map[string]interface{}{"limit": limit}, unConfirmTxs)
+	return unConfirmTxs, err
+}
+
+func (t *httpTransport) NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
+	numUnConfirmTxs := new(ctypes.ResultUnconfirmedTxs)
+	err := t.call("num_unconfirmed_txs", map[string]interface{}{}, numUnConfirmTxs)
+	return numUnConfirmTxs, err
+}
+
+func (t *httpTransport) NetInfo() (*ctypes.ResultNetInfo, error) {
+	netInfo := new(ctypes.ResultNetInfo)
+	err := t.call("net_info", map[string]interface{}{}, netInfo)
+	return netInfo, err
+}
+
+func (t *httpTransport) DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
+	consensusState := new(ctypes.ResultDumpConsensusState)
+	err := t.call("dump_consensus_state", map[string]interface{}{}, consensusState)
+	return consensusState, err
+}
+
+func (t *httpTransport) ConsensusState() (*ctypes.ResultConsensusState, error) {
+	consensusState := new(ctypes.ResultConsensusState)
+	err := t.call("consensus_state", map[string]interface{}{}, consensusState)
+	return consensusState, err
+}
+
+func (t *httpTransport) Health() (*ctypes.ResultHealth, error) {
+	health := new(ctypes.ResultHealth)
+	err := t.call("health", map[string]interface{}{}, health)
+	return health, err
+}
+
+func (t *httpTransport) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+	blocksInfo := new(ctypes.ResultBlockchainInfo)
+	err := t.call("blockchain", map[string]interface{}{"minHeight": minHeight, "maxHeight": maxHeight}, blocksInfo)
+	return blocksInfo, err
+}
+
+func (t *httpTransport) Genesis() (*ctypes.ResultGenesis, error) {
+	genesis := new(ctypes.ResultGenesis)
+	err := t.call("genesis", map[string]interface{}{}, genesis)
+	return genesis, err
+}
+
+func (t *httpTransport) Block(height *int64) (*ctypes.ResultBlock, error) {
+	block := new(ctypes.ResultBlock)
+	err := t.call("block", map[string]interface{}{"height": height}, block)
+	return block, err
+}
+
+func (t *httpTransport) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+	block := new(ctypes.ResultBlockResults)
+	err := t.call("block_results", map[string]interface{}{"height": height}, block)
+	return block, err
+}
+
+func (t *httpTransport) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	commit := new(ctypes.ResultCommit)
+	err := t.call("commit", map[string]interface{}{"height": height}, commit)
+	return commit, err
+}
+
+func (t *httpTransport) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	tx := new(ctypes.ResultTx)
+	err := t.call("tx", map[string]interface{}{"hash": hash, "prove": prove}, tx)
+	return tx, err
+}
+
+func (t *httpTransport) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	txs := new(ctypes.ResultTxSearch)
+	params := map[string]interface{}{
+		"query":    query,
+		"prove":    prove,
+		"page":     page,
+		"per_page": perPage,
+	}
+	err := t.call("tx_search", params, txs)
+	return txs, err
+}
+
+func (t *httpTransport) TxInfoSearch(query string, prove bool, page, perPage int) ([]tx.Info, error) {
+	txs, err := t.TxSearch(query, prove, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+	return FormatTxResults(t.cdc, txs.Txs)
+}
+
+func (t *httpTransport) Validators(height *int64) (*ctypes.ResultValidators, error) {
+	validators := new(ctypes.ResultValidators)
+	err := t.call("validators", map[string]interface{}{"height": height}, validators)
+	return validators, err
+}
@@ -0,0 +1,222 @@
+package rpc_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-amino"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+	ntypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+// rpcServer is a node answering the JSON-RPC calls POSTed to / and sent
+// on /websocket, one request per body or frame like the node does. With
+// batches it also answers the JSON arrays POSTed. handle returns the result
+// of a call.
+type rpcServer struct {
+	*httptest.Server
+
+	cdc     *amino.Codec
+	handle  func(method string, params json.RawMessage) (interface{}, error)
+	batches bool
+
+	mtx     sync.Mutex
+	methods []string
+	posts   int
+	conns   int
+}
+
+func newRPCServer(handle func(method string, params json.RawMessage) (interface{}, error)) *rpcServer {
+	s := &rpcServer{cdc: amino.NewCodec(), handle: handle}
+	ctypes.RegisterAmino(s.cdc)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// answer returns the response to the request body.
+func (s *rpcServer) answer(body []byte) rpctypes.RPCResponse {
+	var request rpctypes.RPCRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return rpctypes.RPCParseError(rpctypes.JSONRPCStringID(""), err)
+	}
+	s.mtx.Lock()
+	s.methods = append(s.methods, request.Method)
+	s.mtx.Unlock()
+	result, err := s.handle(request.Method, request.Params)
+	if err != nil {
+		return rpctypes.RPCInternalError(request.ID, err)
+	}
+	return rpctypes.NewRPCSuccessResponse(s.cdc, request.ID, result)
+}
+
+func (s *rpcServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/websocket" {
+		s.serveWS(w, r)
+		return
+	}
+	if r.Method != http.MethodPost || r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	s.mtx.Lock()
+	s.posts++
+	s.mtx.Unlock()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	var requests []json.RawMessage
+	if s.batches && json.Unmarshal(body, &requests) == nil {
+		responses := make([]rpctypes.RPCResponse, len(requests))
+		for i, request := range requests {
+			responses[i] = s.answer(request)
+		}
+		json.NewEncoder(w).Encode(responses)
+		return
+	}
+	json.NewEncoder(w).Encode(s.answer(body))
+}
+
+// serveWS answers every frame in its own goroutine, so that slow calls are
+// answered after the next ones.
+func (s *rpcServer) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	s.mtx.Lock()
+	s.conns++
+	s.mtx.Unlock()
+	var writeMtx sync.Mutex
+	for {
+		_, frame, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		go func() {
+			response := s.answer(frame)
+			writeMtx.Lock()
+			defer writeMtx.Unlock()
+			conn.WriteJSON(response)
+		}()
+	}
+}
+
+func (s *rpcServer) calledMethods() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]string(nil), s.methods...)
+}
+
+// counts returns the number of HTTP requests and of websocket connections.
+func (s *rpcServer) counts() (posts, conns int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.posts, s.conns
+}
+
+// host is the address of the server without the scheme.
+func (s *rpcServer) host() string {
+	return strings.TrimPrefix(strings.TrimPrefix(s.URL, "http://"), "https://")
+}
+
+func statusHandler(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "status":
+		return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: 42}}, nil
+	case "abci_query":
+		var query struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+		result := &ctypes.ResultABCIQuery{}
+		result.Response.Log = query.Path
+		return result, nil
+	}
+	return nil, errors.New("method not found")
+}
+
+func TestHTTPTransportCalls(t *testing.T) {
+	s := newRPCServer(statusHandler)
+	defer s.Close()
+	c := rpc.NewRPCClient("tcp://"+s.host(), ntypes.TestNetwork, rpc.WithTransport(rpc.TransportHTTP))
+
+	status, err := c.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), status.SyncInfo.LatestBlockHeight)
+
+	result, err := c.ABCIQuery("/store/acc/key", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/store/acc/key", result.Response.Log)
+
+	// the error of the node is returned
+	_, err = c.NetInfo()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "method not found")
+
+	assert.Equal(t, []string{"status", "abci_query", "net_info"}, s.calledMethods())
+}
+
+func TestHTTPTransportBadResponses(t *testing.T) {
+	var status int
+	var body string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer s.Close()
+	c := rpc.NewRPCClient(strings.Replace(s.URL, "http://", "tcp://", 1), ntypes.TestNetwork, rpc.WithTransport(rpc.TransportHTTP))
+
+	status, body = http.StatusBadGateway, ""
+	_, err := c.Status()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+
+	status, body = http.StatusOK, "<html>not json</html>"
+	_, err = c.Status()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode the response of status")
+}
+
+func TestHTTPTransportSchemes(t *testing.T) {
+	s := newRPCServer(statusHandler)
+	defer s.Close()
+
+	// the websocket and http schemes name the same plain HTTP server
+	for _, scheme := range []string{"tcp", "http", "ws"} {
+		c := rpc.NewRPCClient(scheme+"://"+s.host(), ntypes.TestNetwork, rpc.WithTransport(rpc.TransportHTTP))
+		status, err := c.Status()
+		assert.NoError(t, err, scheme)
+		assert.Equal(t, int64(42), status.SyncInfo.LatestBlockHeight, scheme)
+	}
+
+	// wss is sent over TLS: the handshake reaches the test certificate
+	tlsServer := httptest.NewUnstartedServer(http.NotFoundHandler())
+	tlsServer.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	for _, scheme := range []string{"https", "wss"} {
+		c := rpc.NewRPCClient(scheme+"://"+strings.TrimPrefix(tlsServer.URL, "https://"), ntypes.TestNetwork, rpc.WithTransport(rpc.TransportHTTP))
+		_, err := c.Status()
+		if assert.Error(t, err, scheme) {
+			assert.Contains(t, err.Error(), "certificate", scheme)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/lite"
+	liteclient "github.com/tendermint/tendermint/lite/client"
+	lerr "github.com/tendermint/tendermint/lite/errors"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+const trustStoreLabel = "trusted"
+
+// ErrHeaderVerification is returned, wrapped, when a header or a block of the
+// node does not follow from the trusted state.
+var ErrHeaderVerification = errors.New("header verification failed")
+
+// TrustOptions is the header the light client trusts to start with, usually
+// taken from a block explorer or another node.
+type TrustOptions struct {
+	Height int64
+	Hash   cmn.HexBytes
+}
+
+// NewMemTrustStore returns a trust store kept in memory, the trust is lost
+// when the process exits.
+func NewMemTrustStore() lite.PersistentProvider {
+	return lite.NewDBProvider(trustStoreLabel, dbm.NewMemDB())
+}
+
+// NewFileTrustStore returns a trust store kept in a leveldb database in dir.
+func NewFileTrustStore(dir string) (lite.PersistentProvider, error) {
+	db, err := dbm.NewGoLevelDB(trustStoreLabel, dir)
+	if err != nil {
+		return nil, err
+	}
+	return lite.NewDBProvider(trustStoreLabel, db), nil
+}
+
+// LightClient verifies the headers of an untrusted node. From a trusted
+// header it follows the validator set transitions, and accepts a header once
+// +2/3 of the voting power of its trusted validator set signed it. The
+// verified headers and validator sets are persisted in the trust store.
+type LightClient struct {
+	chainID  string
+	client   Client
+	store    lite.PersistentProvider
+	verifier *lite.DynamicVerifier
+}
+
+// NewLightClient returns a light client of the chain chainID, the chain of the
+// node when chainID is empty. A store holding a verified header is resumed and
+// trust may then be left zero, otherwise the header at trust.Height must hash
+// to trust.Hash.
+func NewLightClient(c Client, chainID string, trust TrustOptions, store lite.PersistentProvider) (*LightClient, error) {
+	if chainID == "" {
+		var err error
+		if chainID, err = DiscoverChainID(c); err != nil {
+			return nil, err
+		}
+	}
+	source := liteclient.NewProvider(chainID, c)
+	lc := &LightClient{
+		chainID:  chainID,
+		client:   c,
+		store:    store,
+		verifier: lite.NewDynamicVerifier(chainID, store, source),
+	}
+	_, err := store.LatestFullCommit(chainID, 1, 1<<63-1)
+	if err == nil && trust.Height == 0 {
+		return lc, nil
+	}
+	if err != nil && !lerr.IsErrCommitNotFound(err) {
+		return nil, err
+	}
+	if trust.Height <= 0 || len(trust.Hash) == 0 {
+		return nil, fmt.Errorf("the trust store is empty, a trusted height and hash are required")
+	}
+	fc, err := source.LatestFullCommit(chainID, trust.Height, trust.Height)
+	if err != nil {
+		return nil, err
+	}
+	if fc.Height() != trust.Height {
+		return nil, fmt.Errorf("the node has no header at the trusted height %d", trust.Height)
+	}
+	if hash := fc.SignedHeader.Hash(); !bytes.Equal(hash, trust.Hash) {
+		return nil, fmt.Errorf("%w: the header at height %d hashes to %X instead of the trusted %X", ErrHeaderVerification, trust.Height, hash, []byte(trust.Hash))
+	}
+	if err := fc.ValidateFull(chainID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHeaderVerification, err.Error())
+	}
+	if err := store.SaveFullCommit(fc); err != nil {
+		return nil, err
+	}
+	return lc, nil
+}
+
+func (lc *LightClient) ChainID() string {
+	return lc.chainID
+}
+
+// TrustedHeight is the height of the latest verified header.
+func (lc *LightClient) TrustedHeight() int64 {
+	return lc.verifier.LastTrustedHeight()
+}
+
+// Verify checks that shdr follows from the trusted state.
+func (lc *LightClient) Verify(shdr types.SignedHeader) error {
+	if shdr.Header == nil || shdr.Commit == nil {
+		return fmt.Errorf("%w: incomplete signed header", ErrHeaderVerification)
+	}
+	if err := lc.verifier.Verify(shdr); err != nil {
+		return fmt.Errorf("%w: %s", ErrHeaderVerification, err.Error())
+	}
+	return nil
+}
+
+// SignedHeader returns the verified header at height, the latest one when
+// height is nil.
+func (lc *LightClient) SignedHeader(height *int64) (*types.SignedHeader, error) {
+	commit, err := lc.client.Commit(height)
+	if err != nil {
+		return nil, err
+	}
+	if height != nil && commit.Header != nil && commit.Header.Height != *height {
+		return nil, fmt.Errorf("the node returned the header at height %d instead of %d", commit.Header.Height, *height)
+	}
+	if err := lc.Verify(commit.SignedHeader); err != nil {
+		return nil, err
+	}
+	return &commit.SignedHeader, nil
+}
+
+// Block returns the block at height, checked against its verified header.
+func (lc *LightClient) Block(height *int64) (*ctypes.ResultBlock, error) {
+	block, err := lc.client.Block(height)
+	if err != nil {
+		return nil, err
+	}
+	if block.Block == nil {
+		return nil, fmt.Errorf("the node returned no block")
+	}
+	h := block.Block.Height
+	shdr, err := lc.SignedHeader(&h)
+	if err != nil {
+		return nil, err
+	}
+	if err := block.Block.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHeaderVerification, err.Error())
+	}
+	if hash := block.Block.Hash(); !bytes.Equal(hash, shdr.Hash()) {
+		return nil, fmt.Errorf("%w: block %d hashes to %X instead of %X", ErrHeaderVerification, h, hash, shdr.Hash())
+	}
+	return block, nil
+}
+
+// SetLightClient routes the header fetches of the verified queries through
+// lc. Set it before the client is shared.
+func (c *HTTP) SetLightClient(lc *LightClient) {
+	c.lightClient = lc
+}
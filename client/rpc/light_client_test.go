@@ -0,0 +1,272 @@
+package rpc_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/lite"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+	"github.com/binance-chain/go-sdk/client/rpc/mock"
+)
+
+const lightChainID = "test-chain"
+
+// signedChain is a chain of signed blocks. Its validators are all replaced
+// at rotateAt, so that reaching a later header from an earlier one needs the
+// validator set transition. The validators up to pruned are not served and
+// commitFn may change the commits served.
+type signedChain struct {
+	mock.Client
+
+	blocks   []*types.Block
+	commits  []*types.Commit
+	valsets  []*types.ValidatorSet
+	pruned   int64
+	commitFn func(h int64, commit *ctypes.ResultCommit)
+}
+
+func genKeys(n int) []crypto.PrivKey {
+	keys := make([]crypto.PrivKey, n)
+	for i := range keys {
+		keys[i] = ed25519.GenPrivKey()
+	}
+	return keys
+}
+
+func toValidators(keys []crypto.PrivKey) *types.ValidatorSet {
+	vals := make([]*types.Validator, len(keys))
+	for i, key := range keys {
+		vals[i] = types.NewValidator(key.PubKey(), 10)
+	}
+	return types.NewValidatorSet(vals)
+}
+
+// signHeader returns the commit of header signed by keys, the members of valset.
+func signHeader(header *types.Header, valset *types.ValidatorSet, keys []crypto.PrivKey) *types.Commit {
+	blockID := types.BlockID{Hash: header.Hash()}
+	sigs := make([]*types.CommitSig, valset.Size())
+	for _, key := range keys {
+		addr := key.PubKey().Address()
+		idx, _ := valset.GetByAddress(addr)
+		vote := &types.Vote{
+			ValidatorAddress: addr,
+			ValidatorIndex:   idx,
+			Height:           header.Height,
+			Timestamp:        header.Time,
+			Type:             types.PrecommitType,
+			BlockID:          blockID,
+		}
+		sig, err := key.Sign(vote.SignBytes(header.ChainID))
+		if err != nil {
+			panic(err)
+		}
+		vote.Signature = sig
+		sigs[idx] = vote.CommitSig()
+	}
+	return types.NewCommit(blockID, sigs)
+}
+
+func newSignedChain(height, rotateAt int64) *signedChain {
+	c := &signedChain{}
+	keys := [][]crypto.PrivKey{genKeys(4), genKeys(4)}
+	valsets := []*types.ValidatorSet{toValidators(keys[0]), toValidators(keys[1])}
+	set := func(h int64) int {
+		if h >= rotateAt {
+			return 1
+		}
+		return 0
+	}
+	var lastCommit *types.Commit
+	var lastBlockID types.BlockID
+	for h := int64(1); h <= height; h++ {
+		txs := types.Txs{types.Tx(fmt.Sprintf("tx-%d", h))}
+		block := types.MakeBlock(h, txs, lastCommit, nil)
+		block.ChainID = lightChainID
+		block.Time = time.Date(2019, 7, 1, 12, 0, int(h), 0, time.UTC)
+		block.TotalTxs = h
+		block.LastBlockID = lastBlockID
+		block.ValidatorsHash = valsets[set(h)].Hash()
+		block.NextValidatorsHash = valsets[set(h+1)].Hash()
+		block.AppHash = []byte(fmt.Sprintf("app-hash-%d", h))
+		block.ProposerAddress = keys[set(h)][0].PubKey().Address()
+		commit := signHeader(&block.Header, valsets[set(h)], keys[set(h)])
+
+		c.blocks = append(c.blocks, block)
+		c.commits = append(c.commits, commit)
+		c.valsets = append(c.valsets, valsets[set(h)])
+		lastCommit = commit
+		lastBlockID = commit.BlockID
+	}
+	c.valsets = append(c.valsets, valsets[set(height+1)])
+	return c
+}
+
+func (c *signedChain) height(height *int64) int64 {
+	if height == nil {
+		return int64(len(c.blocks))
+	}
+	return *height
+}
+
+func (c *signedChain) Status() (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: int64(len(c.blocks))}}, nil
+}
+
+func (c *signedChain) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	h := c.height(height)
+	if h < 1 || h > int64(len(c.blocks)) {
+		return nil, fmt.Errorf("height %d must be less than or equal to the current blockchain height %d", h, len(c.blocks))
+	}
+	header := c.blocks[h-1].Header
+	commit := ctypes.NewResultCommit(&header, c.commits[h-1], true)
+	if c.commitFn != nil {
+		c.commitFn(h, commit)
+	}
+	return commit, nil
+}
+
+func (c *signedChain) Validators(height *int64) (*ctypes.ResultValidators, error) {
+	h := c.height(height)
+	if h <= c.pruned || h > int64(len(c.valsets)) {
+		return nil, errors.New("no validators")
+	}
+	return &ctypes.ResultValidators{BlockHeight: h, Validators: c.valsets[h-1].Validators}, nil
+}
+
+func (c *signedChain) Block(height *int64) (*ctypes.ResultBlock, error) {
+	h := c.height(height)
+	if h < 1 || h > int64(len(c.blocks)) {
+		return nil, errors.New("no block")
+	}
+	block := c.blocks[h-1]
+	return &ctypes.ResultBlock{BlockMeta: types.NewBlockMeta(block, block.MakePartSet(types.BlockPartSizeBytes)), Block: block}, nil
+}
+
+func (c *signedChain) trust(h int64) rpc.TrustOptions {
+	return rpc.TrustOptions{Height: h, Hash: c.blocks[h-1].Header.Hash()}
+}
+
+func TestLightClientTrustOptions(t *testing.T) {
+	chain := newSignedChain(10, 6)
+
+	_, err := rpc.NewLightClient(chain, lightChainID, rpc.TrustOptions{}, rpc.NewMemTrustStore())
+	assert.Error(t, err, "an empty store needs a trusted header")
+
+	trust := chain.trust(2)
+	trust.Hash = chain.blocks[2].Hash()
+	_, err = rpc.NewLightClient(chain, lightChainID, trust, rpc.NewMemTrustStore())
+	assert.True(t, errors.Is(err, rpc.ErrHeaderVerification), "the header at the trusted height hashes to another hash: %v", err)
+
+	_, err = rpc.NewLightClient(chain, "other-chain", chain.trust(2), rpc.NewMemTrustStore())
+	assert.Error(t, err)
+
+	lc, err := rpc.NewLightClient(chain, lightChainID, chain.trust(2), rpc.NewMemTrustStore())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), lc.TrustedHeight())
+	assert.Equal(t, lightChainID, lc.ChainID())
+}
+
+func TestLightClientVerifyHeaders(t *testing.T) {
+	chain := newSignedChain(10, 6)
+	lc, err := rpc.NewLightClient(chain, lightChainID, chain.trust(2), rpc.NewMemTrustStore())
+	assert.NoError(t, err)
+
+	// the next header and one across the validator set transition
+	for _, h := range []int64{3, 9} {
+		shdr, err := lc.SignedHeader(&h)
+		assert.NoError(t, err)
+		assert.Equal(t, chain.blocks[h-1].Header.Hash(), shdr.Hash())
+	}
+	assert.Equal(t, int64(9), lc.TrustedHeight())
+	shdr, err := lc.SignedHeader(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), shdr.Height)
+
+	// a header signed by other validators
+	forged := chain.blocks[9].Header
+	forged.AppHash = []byte("forged")
+	keys := genKeys(4)
+	commit := signHeader(&forged, toValidators(keys), keys)
+	err = lc.Verify(types.SignedHeader{Header: &forged, Commit: commit})
+	assert.True(t, errors.Is(err, rpc.ErrHeaderVerification), "%v", err)
+
+	// a header changed after it was signed
+	h := int64(8)
+	chain.commitFn = func(height int64, commit *ctypes.ResultCommit) {
+		if height == h {
+			header := *commit.Header
+			header.AppHash = []byte("forged")
+			commit.Header = &header
+		}
+	}
+	_, err = lc.SignedHeader(&h)
+	assert.True(t, errors.Is(err, rpc.ErrHeaderVerification), "%v", err)
+
+	// a header of another height than the one asked for
+	chain.commitFn = func(height int64, commit *ctypes.ResultCommit) {
+		*commit = *ctypes.NewResultCommit(&chain.blocks[height-2].Header, chain.commits[height-2], true)
+	}
+	_, err = lc.SignedHeader(&h)
+	assert.Error(t, err)
+
+	assert.True(t, errors.Is(lc.Verify(types.SignedHeader{}), rpc.ErrHeaderVerification))
+}
+
+func TestLightClientVerifyBlocks(t *testing.T) {
+	chain := newSignedChain(10, 6)
+	lc, err := rpc.NewLightClient(chain, lightChainID, chain.trust(1), rpc.NewMemTrustStore())
+	assert.NoError(t, err)
+
+	h := int64(7)
+	block, err := lc.Block(&h)
+	assert.NoError(t, err)
+	assert.Equal(t, chain.blocks[h-1].Hash(), block.Block.Hash())
+
+	// a block whose transactions do not match its header
+	forged := types.MakeBlock(h, types.Txs{types.Tx("forged")}, chain.commits[h-2], nil)
+	forged.Header = chain.blocks[h-1].Header
+	chain.blocks[h-1] = forged
+	_, err = lc.Block(&h)
+	assert.True(t, errors.Is(err, rpc.ErrHeaderVerification), "%v", err)
+}
+
+func TestLightClientStoreSurvivesRestart(t *testing.T) {
+	chain := newSignedChain(10, 6)
+	db := dbm.NewMemDB()
+	store := func() lite.PersistentProvider {
+		return lite.NewDBProvider("trusted", db)
+	}
+
+	lc, err := rpc.NewLightClient(chain, lightChainID, chain.trust(2), store())
+	assert.NoError(t, err)
+	h := int64(8)
+	_, err = lc.SignedHeader(&h)
+	assert.NoError(t, err)
+
+	// the restarted client resumes from the store without trust options
+	lc, err = rpc.NewLightClient(chain, lightChainID, rpc.TrustOptions{}, store())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), lc.TrustedHeight())
+
+	// and does not need the transition again: the old validators are gone
+	chain.pruned = 7
+	h = 10
+	shdr, err := lc.SignedHeader(&h)
+	assert.NoError(t, err)
+	assert.Equal(t, chain.blocks[h-1].Header.Hash(), shdr.Hash())
+
+	// trust options given on restart are still checked
+	trust := chain.trust(9)
+	trust.Hash = chain.blocks[7].Header.Hash()
+	_, err = rpc.NewLightClient(chain, lightChainID, trust, store())
+	assert.True(t, errors.Is(err, rpc.ErrHeaderVerification), "%v", err)
+}
@@ -0,0 +1,287 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/msg"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+const (
+	defaultMempoolPollPeriod = 1 * time.Second
+	// a vanished transaction is dropped once the node did not find it in so
+	// many polls in a row, the indexer may lag behind the commit
+	defaultMempoolDropChecks = 3
+)
+
+type MempoolEventType string
+
+const (
+	MempoolTxAdded     MempoolEventType = "added"
+	MempoolTxCommitted MempoolEventType = "committed"
+	MempoolTxDropped   MempoolEventType = "dropped"
+)
+
+// MempoolTx is a decoded transaction seen in the mempool.
+type MempoolTx struct {
+	Hash      cmn.HexBytes `json:"hash"`
+	Tx        tx.StdTx     `json:"tx"`
+	FirstSeen time.Time    `json:"first_seen"`
+}
+
+// MempoolEvent reports a transaction entering the mempool, and leaving it
+// committed in a block or dropped. Height and Result are set when committed.
+type MempoolEvent struct {
+	Type MempoolEventType `json:"type"`
+	MempoolTx
+	Height int64                   `json:"height,omitempty"`
+	Result *abci.ResponseDeliverTx `json:"result,omitempty"`
+}
+
+// MempoolClient is the part of the RPC API used by the mempool monitor.
+type MempoolClient interface {
+	UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error)
+	Tx(hash []byte, prove bool) (*ctypes.ResultTx, error)
+}
+
+type pendingTx struct {
+	MempoolTx
+	misses int
+}
+
+// MempoolMonitor polls the unconfirmed transactions of a node and reports the
+// watched ones when they appear, are committed or are dropped. Without watched
+// addresses or pairs every transaction is watched. The node lists at most
+// 100 transactions, the ones behind them are seen once the first ones left.
+type MempoolMonitor struct {
+	client     MempoolClient
+	pollPeriod time.Duration
+	dropChecks int
+	addresses  map[string]bool
+	pairs      map[string]bool
+	logger     log.Logger
+
+	mtx     sync.Mutex
+	pending map[string]*pendingTx
+	total   int
+}
+
+type MempoolMonitorOption func(*MempoolMonitor)
+
+// WithMempoolPollPeriod sets how often the mempool is polled.
+func WithMempoolPollPeriod(period time.Duration) MempoolMonitorOption {
+	return func(m *MempoolMonitor) {
+		if period > 0 {
+			m.pollPeriod = period
+		}
+	}
+}
+
+// WithWatchedAddresses watches the transactions involving addrs.
+func WithWatchedAddresses(addrs ...types.AccAddress) MempoolMonitorOption {
+	return func(m *MempoolMonitor) {
+		for _, addr := range addrs {
+			m.addresses[addr.String()] = true
+		}
+	}
+}
+
+// WithWatchedPairs watches the orders and listings of pairs, such as "NNB-0AD_BNB".
+func WithWatchedPairs(pairs ...string) MempoolMonitorOption {
+	return func(m *MempoolMonitor) {
+		for _, pair := range pairs {
+			m.pairs[strings.ToUpper(pair)] = true
+		}
+	}
+}
+
+// WithMempoolLogger sets the logger of the failed polls, which are silent by default.
+func WithMempoolLogger(logger log.Logger) MempoolMonitorOption {
+	return func(m *MempoolMonitor) {
+		m.logger = logger
+	}
+}
+
+func NewMempoolMonitor(c MempoolClient, options ...MempoolMonitorOption) *MempoolMonitor {
+	m := &MempoolMonitor{
+		client:     c,
+		pollPeriod: defaultMempoolPollPeriod,
+		dropChecks: defaultMempoolDropChecks,
+		addresses:  make(map[string]bool),
+		pairs:      make(map[string]bool),
+		pending:    make(map[string]*pendingTx),
+		logger:     log.NewNopLogger(),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Run polls the mempool and hands the events to handler until ctx is done or
+// handler returns an error. A failed poll is logged and retried at the next tick.
+func (m *MempoolMonitor) Run(ctx context.Context, handler func(event MempoolEvent) error) error {
+	ticker := time.NewTicker(m.pollPeriod)
+	defer ticker.Stop()
+	for {
+		events, err := m.Poll()
+		if err != nil {
+			m.logger.Error("failed to poll the mempool", "err", err)
+		}
+		for _, event := range events {
+			if err := handler(event); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Poll checks the mempool once and returns the events since the previous poll.
+func (m *MempoolMonitor) Poll() ([]MempoolEvent, error) {
+	res, err := m.client.UnconfirmedTxs(maxUnConfirmedTxs)
+	if err != nil {
+		return nil, err
+	}
+	m.mtx.Lock()
+	m.total = res.Total
+
+	now := time.Now()
+	var events []MempoolEvent
+	seen := make(map[string]bool, len(res.Txs))
+	for _, txBytes := range res.Txs {
+		hash := cmn.HexBytes(txBytes.Hash())
+		key := hash.String()
+		seen[key] = true
+		if _, ok := m.pending[key]; ok {
+			continue
+		}
+		parsedTx, err := ParseTx(tx.Cdc, txBytes)
+		if err != nil {
+			continue
+		}
+		stdTx := parsedTx.(tx.StdTx)
+		if !m.watched(stdTx) {
+			continue
+		}
+		p := &pendingTx{MempoolTx: MempoolTx{Hash: hash, Tx: stdTx, FirstSeen: now}}
+		m.pending[key] = p
+		events = append(events, MempoolEvent{Type: MempoolTxAdded, MempoolTx: p.MempoolTx})
+	}
+
+	var missing []*pendingTx
+	for key, p := range m.pending {
+		if seen[key] {
+			p.misses = 0
+			continue
+		}
+		missing = append(missing, p)
+	}
+	m.mtx.Unlock()
+
+	// look the vanished transactions up without the lock, Pending and Total
+	// do not wait for the node
+	committed := make([]*ctypes.ResultTx, len(missing))
+	errs := make([]error, len(missing))
+	for i, p := range missing {
+		committed[i], errs[i] = m.client.Tx(p.Hash, false)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for i, p := range missing {
+		key := p.Hash.String()
+		if m.pending[key] != p {
+			// reported by a concurrent poll
+			continue
+		}
+		if err := errs[i]; err != nil {
+			if !strings.Contains(strings.ToLower(err.Error()), "not found") {
+				// unknown state, check again at the next poll
+				continue
+			}
+			if p.misses++; p.misses < m.dropChecks {
+				continue
+			}
+			delete(m.pending, key)
+			events = append(events, MempoolEvent{Type: MempoolTxDropped, MempoolTx: p.MempoolTx})
+			continue
+		}
+		delete(m.pending, key)
+		result := committed[i].TxResult
+		events = append(events, MempoolEvent{Type: MempoolTxCommitted, MempoolTx: p.MempoolTx, Height: committed[i].Height, Result: &result})
+	}
+	return events, nil
+}
+
+// Pending returns the watched transactions still in the mempool, oldest first.
+func (m *MempoolMonitor) Pending() []MempoolTx {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	txs := make([]MempoolTx, 0, len(m.pending))
+	for _, p := range m.pending {
+		txs = append(txs, p.MempoolTx)
+	}
+	sort.SliceStable(txs, func(i, j int) bool {
+		return txs[i].FirstSeen.Before(txs[j].FirstSeen)
+	})
+	return txs
+}
+
+// Total returns the size of the mempool at the last poll.
+func (m *MempoolMonitor) Total() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.total
+}
+
+func (m *MempoolMonitor) watched(stdTx tx.StdTx) bool {
+	if len(m.addresses) == 0 && len(m.pairs) == 0 {
+		return true
+	}
+	for _, sm := range stdTx.Msgs {
+		for _, addr := range sm.GetInvolvedAddresses() {
+			if m.addresses[addr.String()] {
+				return true
+			}
+		}
+		if pair := msgPair(sm); pair != "" && m.pairs[strings.ToUpper(pair)] {
+			return true
+		}
+	}
+	return false
+}
+
+// msgPair returns the trading pair of the orders and listings.
+func msgPair(sm msg.Msg) string {
+	switch sm := sm.(type) {
+	case msg.CreateOrderMsg:
+		return sm.Symbol
+	case *msg.CreateOrderMsg:
+		return sm.Symbol
+	case msg.CancelOrderMsg:
+		return sm.Symbol
+	case *msg.CancelOrderMsg:
+		return sm.Symbol
+	case msg.DexListMsg:
+		return fmt.Sprintf("%s_%s", sm.BaseAssetSymbol, sm.QuoteAssetSymbol)
+	case *msg.DexListMsg:
+		return fmt.Sprintf("%s_%s", sm.BaseAssetSymbol, sm.QuoteAssetSymbol)
+	}
+	return ""
+}
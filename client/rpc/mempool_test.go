@@ -0,0 +1,248 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+	ntypes "github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/msg"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+// fakeMempool is a node whose mempool holds txs and whose blocks hold
+// committed. UnconfirmedTxs fails while down.
+type fakeMempool struct {
+	mtx       sync.Mutex
+	txs       types.Txs
+	committed map[string]int64
+	down      bool
+	polls     int
+	// when set, Tx sends on gate once entered and returns after receiving from it
+	gate chan struct{}
+}
+
+func (m *fakeMempool) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.polls++
+	if m.down {
+		return nil, errConnRefused
+	}
+	return &ctypes.ResultUnconfirmedTxs{Count: len(m.txs), Total: len(m.txs), Txs: append(types.Txs(nil), m.txs...)}, nil
+}
+
+func (m *fakeMempool) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	m.mtx.Lock()
+	gate := m.gate
+	m.mtx.Unlock()
+	if gate != nil {
+		gate <- struct{}{}
+		<-gate
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	height, ok := m.committed[string(hash)]
+	if !ok {
+		return nil, errors.New("Tx (" + string(hash) + ") not found")
+	}
+	return &ctypes.ResultTx{Hash: hash, Height: height, TxResult: abci.ResponseDeliverTx{Log: "Msg 0: "}}, nil
+}
+
+func (m *fakeMempool) set(txs ...types.Tx) {
+	m.mtx.Lock()
+	m.txs = txs
+	m.mtx.Unlock()
+}
+
+func (m *fakeMempool) commit(t types.Tx, height int64) {
+	m.mtx.Lock()
+	m.committed[string(t.Hash())] = height
+	m.mtx.Unlock()
+}
+
+func (m *fakeMempool) setDown(down bool) {
+	m.mtx.Lock()
+	m.down = down
+	m.mtx.Unlock()
+}
+
+func (m *fakeMempool) pollCount() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.polls
+}
+
+// sendTx returns an encoded transaction sending from the address of seed.
+func sendTx(t *testing.T, seed byte, memo string) (types.Tx, ntypes.AccAddress) {
+	from := ntypes.AccAddress(append(make([]byte, 19), seed))
+	to := ntypes.AccAddress(append(make([]byte, 19), seed+1))
+	coins := ntypes.Coins{ntypes.Coin{Denom: "BNB", Amount: 10}}
+	sendMsg := msg.CreateSendMsg(from, coins, []msg.Transfer{{ToAddr: to, Coins: coins}})
+	bz, err := tx.Cdc.MarshalBinaryLengthPrefixed(tx.NewStdTx([]msg.Msg{sendMsg}, nil, memo, 0, nil))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return types.Tx(bz), from
+}
+
+func eventTypes(events []rpc.MempoolEvent) []rpc.MempoolEventType {
+	var eventTypes []rpc.MempoolEventType
+	for _, event := range events {
+		eventTypes = append(eventTypes, event.Type)
+	}
+	return eventTypes
+}
+
+func TestMempoolMonitorAddedAndCommitted(t *testing.T) {
+	node := &fakeMempool{committed: make(map[string]int64)}
+	watchedTx, addr := sendTx(t, 1, "watched")
+	otherTx, _ := sendTx(t, 7, "other")
+	m := rpc.NewMempoolMonitor(node, rpc.WithWatchedAddresses(addr))
+
+	node.set(otherTx, watchedTx)
+	events, err := m.Poll()
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, rpc.MempoolTxAdded, events[0].Type)
+		assert.Equal(t, watchedTx.Hash(), events[0].Hash.Bytes())
+		assert.Equal(t, "watched", events[0].Tx.Memo)
+	}
+	assert.Equal(t, 2, m.Total())
+	assert.Len(t, m.Pending(), 1)
+
+	// a transaction still in the mempool is reported once
+	events, err = m.Poll()
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	node.set(otherTx)
+	node.commit(watchedTx, 12)
+	events, err = m.Poll()
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, rpc.MempoolTxCommitted, events[0].Type)
+		assert.Equal(t, int64(12), events[0].Height)
+		assert.Equal(t, "Msg 0: ", events[0].Result.Log)
+	}
+	assert.Empty(t, m.Pending())
+}
+
+func TestMempoolMonitorDropped(t *testing.T) {
+	node := &fakeMempool{committed: make(map[string]int64)}
+	first, _ := sendTx(t, 1, "first")
+	second, _ := sendTx(t, 3, "second")
+	m := rpc.NewMempoolMonitor(node)
+
+	node.set(first, second)
+	events, err := m.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, []rpc.MempoolEventType{rpc.MempoolTxAdded, rpc.MempoolTxAdded}, eventTypes(events))
+
+	// the indexer may lag, first is dropped after three polls not finding it
+	node.set(second)
+	for i := 0; i < 2; i++ {
+		events, err = m.Poll()
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	}
+	// a miss count is reset when the transaction is back in the mempool
+	node.set(first, second)
+	events, _ = m.Poll()
+	assert.Empty(t, events)
+	node.set(second)
+	for i := 0; i < 2; i++ {
+		events, _ = m.Poll()
+		assert.Empty(t, events)
+	}
+	events, err = m.Poll()
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, rpc.MempoolTxDropped, events[0].Type)
+		assert.Equal(t, "first", events[0].Tx.Memo)
+	}
+	pending := m.Pending()
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "second", pending[0].Tx.Memo)
+	}
+}
+
+func TestMempoolMonitorRunSurvivesFailedPolls(t *testing.T) {
+	node := &fakeMempool{committed: make(map[string]int64), down: true}
+	watchedTx, _ := sendTx(t, 1, "watched")
+	node.set(watchedTx)
+	m := rpc.NewMempoolMonitor(node, rpc.WithMempoolPollPeriod(10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		for node.pollCount() < 3 {
+			time.Sleep(time.Millisecond)
+		}
+		node.setDown(false)
+	}()
+	var received []rpc.MempoolEvent
+	errStop := errors.New("stop")
+	err := m.Run(ctx, func(event rpc.MempoolEvent) error {
+		received = append(received, event)
+		return errStop
+	})
+	assert.Equal(t, errStop, err, "the handler error ends the run")
+	assert.True(t, node.pollCount() > 3)
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, rpc.MempoolTxAdded, received[0].Type)
+	}
+
+	// so does the context
+	cancel()
+	node.setDown(true)
+	assert.Equal(t, context.Canceled, m.Run(ctx, func(rpc.MempoolEvent) error { return nil }))
+}
+
+func TestMempoolMonitorReadsDuringLookup(t *testing.T) {
+	node := &fakeMempool{committed: make(map[string]int64)}
+	watchedTx, _ := sendTx(t, 1, "watched")
+	m := rpc.NewMempoolMonitor(node)
+	node.set(watchedTx)
+	_, err := m.Poll()
+	assert.NoError(t, err)
+
+	gate := make(chan struct{})
+	node.mtx.Lock()
+	node.gate = gate
+	node.mtx.Unlock()
+	node.set()
+	node.commit(watchedTx, 12)
+	polled := make(chan []rpc.MempoolEvent, 1)
+	go func() {
+		events, err := m.Poll()
+		assert.NoError(t, err)
+		polled <- events
+	}()
+	<-gate
+
+	// the monitor answers while the node looks the vanished transaction up
+	read := make(chan []rpc.MempoolTx, 1)
+	go func() {
+		read <- m.Pending()
+	}()
+	select {
+	case pending := <-read:
+		assert.Len(t, pending, 1)
+		assert.Equal(t, 0, m.Total())
+	case <-time.After(time.Second):
+		t.Fatal("the read is blocked by the poll")
+	}
+
+	gate <- struct{}{}
+	assert.Equal(t, []rpc.MempoolEventType{rpc.MempoolTxCommitted}, eventTypes(<-polled))
+	assert.Empty(t, m.Pending())
+}
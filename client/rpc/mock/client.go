@@ -70,6 +70,10 @@ func (c Client) Status() (*ctypes.ResultStatus, error) {
 	return core.Status(&rpctypes.Context{})
 }
 
+func (c Client) ChainID() (string, error) {
+	return rpc.DiscoverChainID(c)
+}
+
 func (c Client) ABCIInfo() (*ctypes.ResultABCIInfo, error) {
 	return core.ABCIInfo(&rpctypes.Context{})
 }
@@ -8,7 +8,11 @@ type OpsClient interface {
 	GetDelegatorUnbondingDelegations(delegatorAddr types.AccAddress) ([]types.UnbondingDelegation, error)
 }
 
+// IsActive is always true with the HTTP transport, its calls need no connection.
 func (c *HTTP) IsActive() bool {
+	if c.http != nil {
+		return true
+	}
 	return c.WSEvents.IsActive()
 }
 
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/binance-chain/go-sdk/common/paging"
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// EachToken calls fn on every token of the chain, fetching the pages with
+// ListAllTokens until a page is not full. fn returns paging.ErrStop to end
+// the iteration early.
+func (c *HTTP) EachToken(ctx context.Context, fn func(types.Token) error, options ...paging.Option) error {
+	fetch := func(offset, limit int) (interface{}, int, int, error) {
+		tokens, err := c.ListAllTokens(offset, limit)
+		return tokens, len(tokens), -1, err
+	}
+	return paging.NewPager(options...).Run(ctx, fetch, func(page interface{}) error {
+		for _, token := range page.([]types.Token) {
+			if err := fn(token); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EachTradingPair calls fn on every trading pair of the chain, see EachToken.
+func (c *HTTP) EachTradingPair(ctx context.Context, fn func(types.TradingPair) error, options ...paging.Option) error {
+	fetch := func(offset, limit int) (interface{}, int, int, error) {
+		pairs, err := c.GetTradingPairs(offset, limit)
+		return pairs, len(pairs), -1, err
+	}
+	return paging.NewPager(options...).Run(ctx, fetch, func(page interface{}) error {
+		for _, pair := range page.([]types.TradingPair) {
+			if err := fn(pair); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,462 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	ntypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultMaxBlockLag         = 10
+)
+
+// ErrNoHealthyNode is returned when every node of the pool is down or lagging.
+var ErrNoHealthyNode = errors.New("no healthy node in the pool")
+
+// NodeHealth is the state of a node of the pool at the last health check.
+type NodeHealth struct {
+	URI       string        `json:"uri"`
+	Height    int64         `json:"height"`
+	Latency   time.Duration `json:"latency"`
+	Healthy   bool          `json:"healthy"`
+	Ejected   bool          `json:"ejected"`
+	LastError string        `json:"last_error,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+type poolNode struct {
+	uri    string
+	client Client
+	health NodeHealth
+}
+
+func (n *poolNode) usable() bool {
+	return n.health.Healthy && !n.health.Ejected
+}
+
+type poolSubscription struct {
+	query string
+	out   chan ctypes.ResultEvent
+	quit  chan struct{}
+	// pending is set while the subscription is not on the pinned node
+	pending bool
+}
+
+// Pool is a Client over several nodes. Reads go to the healthiest node and
+// are retried on another one when the node fails, subscriptions are pinned to
+// one node and moved with their queries when it fails.
+type Pool struct {
+	cmn.BaseService
+
+	interval time.Duration
+	maxLag   int64
+	factory  func(uri string) Client
+
+	// subMtx serializes the changes of the subscriptions, it is held during
+	// the calls to the nodes where mtx is not
+	subMtx  sync.Mutex
+	mtx     sync.RWMutex
+	nodes   []*poolNode
+	subNode *poolNode
+	subs    map[string]*poolSubscription
+	quit    chan struct{}
+}
+
+var _ Client = (*Pool)(nil)
+
+type PoolOption func(*Pool)
+
+// WithHealthCheckInterval sets how often the nodes are checked.
+func WithHealthCheckInterval(interval time.Duration) PoolOption {
+	return func(p *Pool) {
+		if interval > 0 {
+			p.interval = interval
+		}
+	}
+}
+
+// WithMaxBlockLag ejects the nodes more than lag blocks behind the highest node.
+func WithMaxBlockLag(lag int64) PoolOption {
+	return func(p *Pool) {
+		if lag >= 0 {
+			p.maxLag = lag
+		}
+	}
+}
+
+// WithPoolClientFactory sets the function creating the client of a node,
+// NewHTTP with the "/websocket" endpoint by default.
+func WithPoolClientFactory(factory func(uri string) Client) PoolOption {
+	return func(p *Pool) {
+		p.factory = factory
+	}
+}
+
+// NewPool returns a started pool over nodeURIs, each in the form tcp://<host>:<port>.
+func NewPool(nodeURIs []string, network ntypes.ChainNetwork, options ...PoolOption) (*Pool, error) {
+	if len(nodeURIs) == 0 {
+		return nil, fmt.Errorf("the pool needs at least one node")
+	}
+	ntypes.Network = network
+	p := &Pool{
+		interval: defaultHealthCheckInterval,
+		maxLag:   defaultMaxBlockLag,
+		factory: func(uri string) Client {
+			return NewHTTP(uri, "/websocket")
+		},
+		subs: make(map[string]*poolSubscription),
+	}
+	p.BaseService = *cmn.NewBaseService(nil, "RPCPool", p)
+	for _, option := range options {
+		option(p)
+	}
+	for _, uri := range nodeURIs {
+		p.nodes = append(p.nodes, &poolNode{uri: uri, client: p.factory(uri), health: NodeHealth{URI: uri}})
+	}
+	p.CheckHealth()
+	if err := p.Start(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Pool) OnStart() error {
+	p.quit = make(chan struct{})
+	go p.healthRoutine()
+	return nil
+}
+
+func (p *Pool) OnStop() {
+	close(p.quit)
+	p.subMtx.Lock()
+	defer p.subMtx.Unlock()
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for _, sub := range p.subs {
+		close(sub.quit)
+	}
+	p.subs = make(map[string]*poolSubscription)
+	for _, node := range p.nodes {
+		node.client.Stop()
+	}
+}
+
+func (p *Pool) healthRoutine() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.CheckHealth()
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// Nodes returns the state of the nodes at the last health check.
+func (p *Pool) Nodes() []NodeHealth {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	health := make([]NodeHealth, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		health = append(health, node.health)
+	}
+	return health
+}
+
+// CheckHealth checks every node now, ejects the lagging ones, moves the
+// subscriptions away from a failed node and retries the subscriptions that
+// failed to move.
+func (p *Pool) CheckHealth() {
+	results := make([]NodeHealth, len(p.nodes))
+	var wg sync.WaitGroup
+	for i, node := range p.nodes {
+		wg.Add(1)
+		go func(i int, node *poolNode) {
+			defer wg.Done()
+			results[i] = checkNode(node)
+		}(i, node)
+	}
+	wg.Wait()
+
+	var tip int64
+	for _, health := range results {
+		if health.Healthy && health.Height > tip {
+			tip = health.Height
+		}
+	}
+	p.mtx.Lock()
+	for i, node := range p.nodes {
+		results[i].Ejected = results[i].Healthy && tip-results[i].Height > p.maxLag
+		node.health = results[i]
+	}
+	failover := p.subNode != nil && !p.subNode.usable()
+	for _, sub := range p.subs {
+		failover = failover || sub.pending
+	}
+	p.mtx.Unlock()
+	if failover {
+		p.resubscribe()
+	}
+}
+
+func checkNode(node *poolNode) NodeHealth {
+	health := NodeHealth{URI: node.uri, CheckedAt: time.Now()}
+	start := time.Now()
+	status, err := node.client.Status()
+	health.Latency = time.Since(start)
+	if err == nil {
+		if checker, ok := node.client.(interface {
+			Health() (*ctypes.ResultHealth, error)
+		}); ok {
+			_, err = checker.Health()
+		}
+	}
+	switch {
+	case err != nil:
+		health.LastError = err.Error()
+	case status.SyncInfo.CatchingUp:
+		health.Height = status.SyncInfo.LatestBlockHeight
+		health.LastError = "the node is catching up"
+	default:
+		health.Height = status.SyncInfo.LatestBlockHeight
+		health.Healthy = true
+	}
+	return health
+}
+
+// candidates returns the usable nodes, the healthiest first: the highest ones,
+// then the fastest ones.
+func (p *Pool) candidates() []*poolNode {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	nodes := make([]*poolNode, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		if node.usable() {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		hi, hj := nodes[i].health, nodes[j].health
+		if hi.Height != hj.Height {
+			return hi.Height > hj.Height
+		}
+		return hi.Latency < hj.Latency
+	})
+	return nodes
+}
+
+func (p *Pool) markFailed(node *poolNode, err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	node.health.Healthy = false
+	node.health.LastError = err.Error()
+}
+
+// read runs fn on the healthiest node, and on the next ones while the node fails.
+func (p *Pool) read(fn func(c Client) error) error {
+	nodes := p.candidates()
+	if len(nodes) == 0 {
+		return ErrNoHealthyNode
+	}
+	var err error
+	for _, node := range nodes {
+		if err = fn(node.client); err == nil || !isNodeFailure(node.client, err) {
+			return err
+		}
+		p.markFailed(node, err)
+	}
+	return err
+}
+
+// once runs fn on the healthiest node only, a broadcast must not be sent twice.
+func (p *Pool) once(fn func(c Client) error) error {
+	nodes := p.candidates()
+	if len(nodes) == 0 {
+		return ErrNoHealthyNode
+	}
+	err := fn(nodes[0].client)
+	if err != nil && isNodeFailure(nodes[0].client, err) {
+		p.markFailed(nodes[0], err)
+	}
+	return err
+}
+
+func isNodeFailure(c Client, err error) bool {
+	if !c.IsActive() {
+		return true
+	}
+	msg := err.Error()
+	for _, failure := range []string{"deadline exceeded", "connection refused", "connection reset", "broken pipe", "EOF"} {
+		if strings.Contains(msg, failure) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Pool) IsActive() bool {
+	return len(p.candidates()) > 0
+}
+
+// Subscribe subscribes on the pinned node. The returned channel keeps
+// receiving the events when the subscription moves to another node, some
+// events may be missed or repeated during the move.
+func (p *Pool) Subscribe(query string, outCapacity ...int) (chan ctypes.ResultEvent, error) {
+	p.subMtx.Lock()
+	defer p.subMtx.Unlock()
+	p.mtx.RLock()
+	_, ok := p.subs[query]
+	p.mtx.RUnlock()
+	if ok {
+		return nil, errors.New("already subscribe")
+	}
+	node, err := p.repin()
+	if err != nil {
+		return nil, err
+	}
+	in, err := node.client.Subscribe(query, outCapacity...)
+	if err != nil {
+		return nil, err
+	}
+	outCap := 1
+	if len(outCapacity) > 0 {
+		outCap = outCapacity[0]
+	}
+	sub := &poolSubscription{query: query, out: make(chan ctypes.ResultEvent, outCap), quit: make(chan struct{})}
+	p.mtx.Lock()
+	p.subs[query] = sub
+	p.mtx.Unlock()
+	go sub.forward(in, sub.quit)
+	return sub.out, nil
+}
+
+func (p *Pool) Unsubscribe(query string) error {
+	p.subMtx.Lock()
+	defer p.subMtx.Unlock()
+	p.mtx.Lock()
+	sub, ok := p.subs[query]
+	if ok {
+		close(sub.quit)
+		delete(p.subs, query)
+	}
+	node := p.subNode
+	p.mtx.Unlock()
+	if !ok {
+		return errors.New("subscription not found")
+	}
+	if sub.pending {
+		return nil
+	}
+	return node.client.Unsubscribe(query)
+}
+
+func (p *Pool) UnsubscribeAll() error {
+	p.subMtx.Lock()
+	defer p.subMtx.Unlock()
+	p.mtx.Lock()
+	for query, sub := range p.subs {
+		close(sub.quit)
+		delete(p.subs, query)
+	}
+	node := p.subNode
+	p.mtx.Unlock()
+	if node == nil {
+		return nil
+	}
+	return node.client.UnsubscribeAll()
+}
+
+// pinnedNode returns the node the subscriptions should be on: the pinned node
+// while it is usable, else the healthiest node. p.mtx must be held.
+func (p *Pool) pinnedNode() (*poolNode, error) {
+	if p.subNode != nil && p.subNode.usable() {
+		return p.subNode, nil
+	}
+	var best *poolNode
+	for _, node := range p.nodes {
+		if node.usable() && (best == nil || node.health.Height > best.health.Height ||
+			(node.health.Height == best.health.Height && node.health.Latency < best.health.Latency)) {
+			best = node
+		}
+	}
+	if best == nil {
+		return nil, ErrNoHealthyNode
+	}
+	return best, nil
+}
+
+// resubscribe moves the subscriptions to a new pinned node, or retries the
+// pending ones on the pinned node.
+func (p *Pool) resubscribe() {
+	p.subMtx.Lock()
+	defer p.subMtx.Unlock()
+	p.repin()
+}
+
+// repin returns the pinned node, after moving the subscriptions to it when it
+// changed and subscribing the pending ones. A subscription failing to move
+// stays pending and is retried by the next health check. p.subMtx must be
+// held, p.mtx is only held to read and update the state, not during the calls
+// to the nodes.
+func (p *Pool) repin() (*poolNode, error) {
+	p.mtx.RLock()
+	old := p.subNode
+	node, err := p.pinnedNode()
+	var moves []*poolSubscription
+	for _, sub := range p.subs {
+		if node != old || sub.pending {
+			moves = append(moves, sub)
+		}
+	}
+	p.mtx.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if node != old && old != nil {
+		old.client.UnsubscribeAll()
+	}
+	for _, sub := range moves {
+		close(sub.quit)
+		quit := make(chan struct{})
+		in, err := node.client.Subscribe(sub.query, cap(sub.out))
+		p.mtx.Lock()
+		sub.quit = quit
+		sub.pending = err != nil
+		p.mtx.Unlock()
+		if err != nil {
+			p.Logger.Error("failed to move the subscription", "query", sub.query, "node", node.uri, "err", err)
+			continue
+		}
+		go sub.forward(in, quit)
+	}
+	p.mtx.Lock()
+	p.subNode = node
+	p.mtx.Unlock()
+	return node, nil
+}
+
+func (sub *poolSubscription) forward(in chan ctypes.ResultEvent, quit chan struct{}) {
+	for {
+		select {
+		case event := <-in:
+			select {
+			case sub.out <- event:
+			case <-quit:
+				return
+			}
+		case <-quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package rpc
+
+import (
+	"context"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/common/paging"
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+// The queries go through read and may be answered by any node of the pool,
+// the broadcasts go through once and are sent once.
+
+func (p *Pool) ABCIInfo() (res *ctypes.ResultABCIInfo, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.ABCIInfo()
+		return
+	})
+	return
+}
+
+func (p *Pool) ABCIQuery(path string, data cmn.HexBytes) (res *ctypes.ResultABCIQuery, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.ABCIQuery(path, data)
+		return
+	})
+	return
+}
+
+func (p *Pool) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts client.ABCIQueryOptions) (res *ctypes.ResultABCIQuery, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.ABCIQueryWithOptions(path, data, opts)
+		return
+	})
+	return
+}
+
+func (p *Pool) Block(height *int64) (res *ctypes.ResultBlock, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.Block(height)
+		return
+	})
+	return
+}
+
+func (p *Pool) BlockResults(height *int64) (res *ctypes.ResultBlockResults, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.BlockResults(height)
+		return
+	})
+	return
+}
+
+func (p *Pool) Commit(height *int64) (res *ctypes.ResultCommit, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.Commit(height)
+		return
+	})
+	return
+}
+
+func (p *Pool) Validators(height *int64) (res *ctypes.ResultValidators, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.Validators(height)
+		return
+	})
+	return
+}
+
+func (p *Pool) Tx(hash []byte, prove bool) (res *ctypes.ResultTx, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.Tx(hash, prove)
+		return
+	})
+	return
+}
+
+func (p *Pool) TxSearch(query string, prove bool, page, perPage int) (res *ctypes.ResultTxSearch, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.TxSearch(query, prove, page, perPage)
+		return
+	})
+	return
+}
+
+func (p *Pool) Genesis() (res *ctypes.ResultGenesis, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.Genesis()
+		return
+	})
+	return
+}
+
+func (p *Pool) BlockchainInfo(minHeight, maxHeight int64) (res *ctypes.ResultBlockchainInfo, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.BlockchainInfo(minHeight, maxHeight)
+		return
+	})
+	return
+}
+
+func (p *Pool) ChainID() (res string, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.ChainID()
+		return
+	})
+	return
+}
+
+func (p *Pool) Status() (res *ctypes.ResultStatus, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.Status()
+		return
+	})
+	return
+}
+
+func (p *Pool) TxInfoSearch(query string, prove bool, page, perPage int) (res []tx.Info, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.TxInfoSearch(query, prove, page, perPage)
+		return
+	})
+	return
+}
+
+func (p *Pool) ListAllTokens(offset int, limit int) (res []types.Token, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.ListAllTokens(offset, limit)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetTokenInfo(symbol string) (res *types.Token, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetTokenInfo(symbol)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetAccount(addr types.AccAddress) (res types.Account, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetAccount(addr)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetCommitAccount(addr types.AccAddress) (res types.Account, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetCommitAccount(addr)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetVerifiedCommitAccount(addr types.AccAddress) (res types.Account, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetVerifiedCommitAccount(addr)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetVerifiedTokenInfo(symbol string) (res *types.Token, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetVerifiedTokenInfo(symbol)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetBalances(addr types.AccAddress) (res []types.TokenBalance, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetBalances(addr)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetBalance(addr types.AccAddress, symbol string) (res *types.TokenBalance, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetBalance(addr, symbol)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetFee() (res []types.FeeParam, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetFee()
+		return
+	})
+	return
+}
+
+func (p *Pool) GetOpenOrders(addr types.AccAddress, pair string) (res []types.OpenOrder, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetOpenOrders(addr, pair)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetTradingPairs(offset int, limit int) (res []types.TradingPair, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetTradingPairs(offset, limit)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetDepth(tradePair string, level int) (res *types.OrderBook, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetDepth(tradePair, level)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetProposals(status types.ProposalStatus, numLatest int64) (res []types.Proposal, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetProposals(status, numLatest)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetProposal(proposalId int64) (res types.Proposal, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetProposal(proposalId)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetTimelocks(address string) (res []types.TimeLockRecord, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetTimelocks(address)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetTimelock(address string, recordID int64) (res types.TimeLockRecord, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetTimelock(address, recordID)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetAccountAtHeight(addr types.AccAddress, height int64) (res types.Account, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetAccountAtHeight(addr, height)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetBalancesAtHeight(addr types.AccAddress, height int64) (res []types.TokenBalance, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetBalancesAtHeight(addr, height)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetTokenInfoAtHeight(symbol string, height int64) (res *types.Token, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetTokenInfoAtHeight(symbol, height)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetTradingPairsAtHeight(offset int, limit int, height int64) (res []types.TradingPair, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetTradingPairsAtHeight(offset, limit, height)
+		return
+	})
+	return
+}
+
+func (p *Pool) GetStakeValidators() (res []types.Validator, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetStakeValidators()
+		return
+	})
+	return
+}
+
+func (p *Pool) GetDelegatorUnbondingDelegations(delegatorAddr types.AccAddress) (res []types.UnbondingDelegation, err error) {
+	err = p.read(func(c Client) (err error) {
+		res, err = c.GetDelegatorUnbondingDelegations(delegatorAddr)
+		return
+	})
+	return
+}
+
+func (p *Pool) BroadcastTxCommit(tx tmtypes.Tx) (res *ctypes.ResultBroadcastTxCommit, err error) {
+	err = p.once(func(c Client) (err error) {
+		res, err = c.BroadcastTxCommit(tx)
+		return
+	})
+	return
+}
+
+func (p *Pool) BroadcastTxAsync(tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	err = p.once(func(c Client) (err error) {
+		res, err = c.BroadcastTxAsync(tx)
+		return
+	})
+	return
+}
+
+func (p *Pool) BroadcastTxSync(tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	err = p.once(func(c Client) (err error) {
+		res, err = c.BroadcastTxSync(tx)
+		return
+	})
+	return
+}
+
+// EachToken runs on one node, a failing node is not replaced during the walk.
+func (p *Pool) EachToken(ctx context.Context, fn func(types.Token) error, options ...paging.Option) error {
+	return p.once(func(c Client) error {
+		return c.EachToken(ctx, fn, options...)
+	})
+}
+
+// EachTradingPair runs on one node, a failing node is not replaced during the walk.
+func (p *Pool) EachTradingPair(ctx context.Context, fn func(types.TradingPair) error, options ...paging.Option) error {
+	return p.once(func(c Client) error {
+		return c.EachTradingPair(ctx, fn, options...)
+	})
+}
@@ -0,0 +1,344 @@
+package rpc_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+	"github.com/binance-chain/go-sdk/client/rpc/mock"
+	ntypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+var errConnRefused = errors.New("dial tcp: connection refused")
+
+// poolNodeClient is a node of a pool test. Every call fails while the node is
+// down, and Subscribe fails while subscribeErrs is positive. When gate is set,
+// Subscribe sends on it once entered and returns after receiving from it.
+type poolNodeClient struct {
+	mock.Client
+
+	uri string
+
+	mtx           sync.Mutex
+	height        int64
+	down          bool
+	subscribeErrs int
+	gate          chan struct{}
+	queries       int
+	broadcasts    int
+	subs          map[string]chan ctypes.ResultEvent
+}
+
+func (c *poolNodeClient) set(height int64, down bool) {
+	c.mtx.Lock()
+	c.height = height
+	c.down = down
+	c.mtx.Unlock()
+}
+
+func (c *poolNodeClient) Stop() error {
+	return nil
+}
+
+func (c *poolNodeClient) Status() (*ctypes.ResultStatus, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.down {
+		return nil, errConnRefused
+	}
+	status := &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: c.height}}
+	status.NodeInfo.Network = "test-chain"
+	return status, nil
+}
+
+func (c *poolNodeClient) ChainID() (string, error) {
+	return rpc.DiscoverChainID(c)
+}
+
+func (c *poolNodeClient) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.queries++
+	if c.down {
+		return nil, errConnRefused
+	}
+	result := &ctypes.ResultABCIQuery{}
+	result.Response.Log = c.uri
+	return result, nil
+}
+
+func (c *poolNodeClient) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.broadcasts++
+	if c.down {
+		return nil, errConnRefused
+	}
+	return &ctypes.ResultBroadcastTx{Log: c.uri}, nil
+}
+
+func (c *poolNodeClient) Subscribe(query string, outCapacity ...int) (chan ctypes.ResultEvent, error) {
+	c.mtx.Lock()
+	gate := c.gate
+	c.mtx.Unlock()
+	if gate != nil {
+		gate <- struct{}{}
+		<-gate
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.down {
+		return nil, errConnRefused
+	}
+	if c.subscribeErrs > 0 {
+		c.subscribeErrs--
+		return nil, errors.New("subscription failed")
+	}
+	out := make(chan ctypes.ResultEvent, 1)
+	c.subs[query] = out
+	return out, nil
+}
+
+func (c *poolNodeClient) Unsubscribe(query string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.subs, query)
+	return nil
+}
+
+func (c *poolNodeClient) UnsubscribeAll() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.subs = make(map[string]chan ctypes.ResultEvent)
+	return nil
+}
+
+// publish sends an event to the subscription query, it reports whether the
+// node has the subscription.
+func (c *poolNodeClient) publish(query string) bool {
+	c.mtx.Lock()
+	out, ok := c.subs[query]
+	c.mtx.Unlock()
+	if ok {
+		out <- ctypes.ResultEvent{Query: query, Data: c.uri}
+	}
+	return ok
+}
+
+func (c *poolNodeClient) counts() (queries, broadcasts int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.queries, c.broadcasts
+}
+
+// newTestPool returns a pool over nodes at heights, the health routine does
+// not run during the test: call CheckHealth.
+func newTestPool(t *testing.T, heights ...int64) (*rpc.Pool, []*poolNodeClient) {
+	nodes := make(map[string]*poolNodeClient)
+	var uris []string
+	for i, height := range heights {
+		uri := string(rune('a' + i))
+		uris = append(uris, uri)
+		nodes[uri] = &poolNodeClient{uri: uri, height: height, subs: make(map[string]chan ctypes.ResultEvent)}
+	}
+	pool, err := rpc.NewPool(uris, ntypes.TestNetwork,
+		rpc.WithHealthCheckInterval(time.Hour),
+		rpc.WithMaxBlockLag(10),
+		rpc.WithPoolClientFactory(func(uri string) rpc.Client {
+			return nodes[uri]
+		}))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	clients := make([]*poolNodeClient, len(uris))
+	for i, uri := range uris {
+		clients[i] = nodes[uri]
+	}
+	return pool, clients
+}
+
+func queriedNode(t *testing.T, pool *rpc.Pool) string {
+	result, err := pool.ABCIQuery("/test", nil)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	return result.Response.Log
+}
+
+func TestPoolEjectsLaggingNodes(t *testing.T) {
+	pool, nodes := newTestPool(t, 100, 95, 88)
+	defer pool.Stop()
+
+	health := pool.Nodes()
+	assert.Len(t, health, 3)
+	assert.False(t, health[0].Ejected)
+	assert.False(t, health[1].Ejected)
+	assert.True(t, health[2].Ejected, "c lags 12 blocks")
+	assert.True(t, health[2].Healthy)
+	assert.Equal(t, "a", queriedNode(t, pool))
+
+	// the highest node goes down, the lag is measured against the new tip
+	nodes[0].set(100, true)
+	pool.CheckHealth()
+	health = pool.Nodes()
+	assert.False(t, health[0].Healthy)
+	assert.False(t, health[2].Ejected)
+	assert.Equal(t, "b", queriedNode(t, pool))
+
+	// c catches up and is the highest node
+	nodes[0].set(100, false)
+	nodes[2].set(110, false)
+	pool.CheckHealth()
+	health = pool.Nodes()
+	assert.True(t, health[1].Ejected, "b lags 15 blocks")
+	assert.Equal(t, "c", queriedNode(t, pool))
+
+	for _, node := range nodes {
+		node.set(0, true)
+	}
+	pool.CheckHealth()
+	_, err := pool.ABCIQuery("/test", nil)
+	assert.Equal(t, rpc.ErrNoHealthyNode, err)
+	assert.False(t, pool.IsActive())
+}
+
+func TestPoolReadRetry(t *testing.T) {
+	pool, nodes := newTestPool(t, 100, 99)
+	defer pool.Stop()
+
+	// a fails between two health checks, the query is retried on b
+	nodes[0].set(100, true)
+	assert.Equal(t, "b", queriedNode(t, pool))
+	queries, _ := nodes[0].counts()
+	assert.Equal(t, 1, queries)
+	health := pool.Nodes()
+	assert.False(t, health[0].Healthy)
+	assert.Contains(t, health[0].LastError, "connection refused")
+
+	// a is not tried again until it passes a health check
+	assert.Equal(t, "b", queriedNode(t, pool))
+	queries, _ = nodes[0].counts()
+	assert.Equal(t, 1, queries)
+
+	nodes[0].set(100, false)
+	pool.CheckHealth()
+	assert.Equal(t, "a", queriedNode(t, pool))
+}
+
+func TestPoolChainID(t *testing.T) {
+	pool, nodes := newTestPool(t, 100, 99)
+	defer pool.Stop()
+
+	nodes[0].set(100, true)
+	chainID, err := pool.ChainID()
+	assert.NoError(t, err)
+	assert.Equal(t, "test-chain", chainID)
+}
+
+func TestPoolBroadcastOnce(t *testing.T) {
+	pool, nodes := newTestPool(t, 100, 99)
+	defer pool.Stop()
+
+	result, err := pool.BroadcastTxSync(types.Tx("tx"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", result.Log)
+
+	// a broadcast failing on a is not sent to b
+	nodes[0].set(100, true)
+	_, err = pool.BroadcastTxSync(types.Tx("tx"))
+	assert.Error(t, err)
+	_, broadcasts := nodes[0].counts()
+	assert.Equal(t, 2, broadcasts)
+	_, broadcasts = nodes[1].counts()
+	assert.Equal(t, 0, broadcasts)
+
+	// the next one goes to b
+	result, err = pool.BroadcastTxSync(types.Tx("tx"))
+	assert.NoError(t, err)
+	assert.Equal(t, "b", result.Log)
+}
+
+func receive(t *testing.T, events chan ctypes.ResultEvent) ctypes.ResultEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	return ctypes.ResultEvent{}
+}
+
+func TestPoolSubscriptionFailover(t *testing.T) {
+	pool, nodes := newTestPool(t, 100, 99)
+	defer pool.Stop()
+	q := query.MustParse("tm.event = 'NewBlock'").String()
+
+	events, err := pool.Subscribe(q)
+	assert.NoError(t, err)
+	assert.True(t, nodes[0].publish(q))
+	assert.Equal(t, "a", receive(t, events).Data)
+
+	// a fails and the subscription fails to move to b
+	nodes[0].set(100, true)
+	nodes[1].subscribeErrs = 1
+	pool.CheckHealth()
+	assert.False(t, nodes[1].publish(q))
+
+	// it is retried by the next health check, on the same channel
+	pool.CheckHealth()
+	assert.True(t, nodes[1].publish(q))
+	assert.Equal(t, "b", receive(t, events).Data)
+
+	// a is back, the subscription stays on b
+	nodes[0].set(101, false)
+	pool.CheckHealth()
+	assert.False(t, nodes[0].publish(q))
+	assert.True(t, nodes[1].publish(q))
+	assert.Equal(t, "b", receive(t, events).Data)
+
+	assert.NoError(t, pool.Unsubscribe(q))
+	assert.False(t, nodes[1].publish(q))
+}
+
+func TestPoolReadsDuringSubscribe(t *testing.T) {
+	pool, nodes := newTestPool(t, 100, 99)
+	defer pool.Stop()
+	q := query.MustParse("tm.event = 'NewBlock'").String()
+
+	gate := make(chan struct{})
+	nodes[0].mtx.Lock()
+	nodes[0].gate = gate
+	nodes[0].mtx.Unlock()
+	subscribed := make(chan error, 1)
+	go func() {
+		_, err := pool.Subscribe(q)
+		subscribed <- err
+	}()
+	<-gate
+
+	// the pool keeps serving while the node is slow to subscribe
+	read := make(chan error, 1)
+	go func() {
+		pool.Nodes()
+		_, err := pool.ABCIQuery("/store/acc/key", nil)
+		read <- err
+	}()
+	select {
+	case err := <-read:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the read is blocked by the subscription")
+	}
+
+	gate <- struct{}{}
+	assert.NoError(t, <-subscribed)
+	assert.True(t, nodes[0].publish(q))
+}
@@ -0,0 +1,146 @@
+package rpc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/common/types"
+)
+
+// Query builds the query strings of Subscribe and TxSearch. The conditions are
+// joined with AND, and the first invalid one is reported by Build.
+type Query struct {
+	conditions []string
+	err        error
+}
+
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// EventType matches the events of a type, such as tmtypes.EventTx.
+func (q *Query) EventType(eventType string) *Query {
+	return q.Eq(tmtypes.EventTypeKey, eventType)
+}
+
+// TxHeight matches the transactions of the block at height.
+func (q *Query) TxHeight(height int64) *Query {
+	return q.Eq(tmtypes.TxHeightKey, height)
+}
+
+// TxHash matches the transaction with hash.
+func (q *Query) TxHash(hash []byte) *Query {
+	return q.Eq(tmtypes.TxHashKey, fmt.Sprintf("%X", hash))
+}
+
+// Tag matches the transactions whose messages set the tag key to value.
+func (q *Query) Tag(key, value string) *Query {
+	return q.Eq(key, value)
+}
+
+// Address matches the transactions whose messages set the tag key to addr.
+func (q *Query) Address(key string, addr types.AccAddress) *Query {
+	return q.Eq(key, addr.String())
+}
+
+func (q *Query) Eq(key string, value interface{}) *Query {
+	return q.condition(key, "=", value)
+}
+
+func (q *Query) Gt(key string, value interface{}) *Query {
+	return q.condition(key, ">", value)
+}
+
+func (q *Query) Gte(key string, value interface{}) *Query {
+	return q.condition(key, ">=", value)
+}
+
+func (q *Query) Lt(key string, value interface{}) *Query {
+	return q.condition(key, "<", value)
+}
+
+func (q *Query) Lte(key string, value interface{}) *Query {
+	return q.condition(key, "<=", value)
+}
+
+func (q *Query) Contains(key string, value string) *Query {
+	return q.condition(key, "CONTAINS", value)
+}
+
+// And adds the conditions of other.
+func (q *Query) And(other *Query) *Query {
+	if other == nil {
+		return q
+	}
+	if q.err == nil {
+		q.err = other.err
+	}
+	q.conditions = append(q.conditions, other.conditions...)
+	return q
+}
+
+func (q *Query) condition(key, op string, value interface{}) *Query {
+	if q.err != nil {
+		return q
+	}
+	if key == "" || strings.ContainsAny(key, " \t\n\r\\()\"'=><") {
+		q.err = fmt.Errorf("invalid query key %q", key)
+		return q
+	}
+	operand, err := formatOperand(value)
+	if err != nil {
+		q.err = fmt.Errorf("invalid query value of %s: %s", key, err.Error())
+		return q
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf("%s %s %s", key, op, operand))
+	return q
+}
+
+func formatOperand(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, "'\"") {
+			return "", fmt.Errorf("quotes are not allowed in %q", v)
+		}
+		return "'" + v + "'", nil
+	case int, int8, int16, int32, int64:
+		if reflect.ValueOf(v).Int() < 0 {
+			return "", fmt.Errorf("negative numbers are not allowed")
+		}
+		return fmt.Sprintf("%d", v), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), nil
+	case time.Time:
+		return "TIME " + v.Format(time.RFC3339), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// String returns the query without checking it.
+func (q *Query) String() string {
+	return strings.Join(q.conditions, " AND ")
+}
+
+// Build returns the query once checked.
+func (q *Query) Build() (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if len(q.conditions) == 0 {
+		return "", fmt.Errorf("the query has no condition")
+	}
+	query := q.String()
+	if err := ValidateTxSearchQueryStr(query); err != nil {
+		return "", err
+	}
+	if _, err := tmquery.New(query); err != nil {
+		return "", err
+	}
+	return query, nil
+}
@@ -0,0 +1,90 @@
+package rpc_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+)
+
+func TestQueryBuild(t *testing.T) {
+	query, err := rpc.NewQuery().EventType(tmtypes.EventTx).TxHeight(100).Tag("action", "orderNew").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "tm.event = 'Tx' AND tx.height = 100 AND action = 'orderNew'", query)
+
+	query, err = rpc.NewQuery().TxHash([]byte{0xab, 0x01}).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "tx.hash = 'AB01'", query)
+
+	_, err = rpc.NewQuery().Build()
+	assert.Error(t, err, "a query needs a condition")
+
+	_, err = rpc.NewQuery().Tag("memo", strings.Repeat("a", 1024)).Build()
+	assert.Equal(t, rpc.ExceedTxSearchQueryStrLengthError, err)
+}
+
+func TestQueryKeys(t *testing.T) {
+	for _, key := range []string{"", "tx height", "a=b", "a>b", "a<b", "a'b", "a\"b", "f(x)", `a\b`, "a\tb"} {
+		_, err := rpc.NewQuery().Eq(key, "v").Build()
+		assert.Error(t, err, "%q", key)
+	}
+	for _, key := range []string{"tm.event", "tx.height", "transfer.sender", "order_id"} {
+		_, err := rpc.NewQuery().Eq(key, "v").Build()
+		assert.NoError(t, err, key)
+	}
+}
+
+func TestQueryOperands(t *testing.T) {
+	query, err := rpc.NewQuery().Contains("memo", "bnb order").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "memo CONTAINS 'bnb order'", query)
+
+	// quotes can not be escaped
+	for _, value := range []string{"it's", `say "hi"`} {
+		_, err := rpc.NewQuery().Tag("memo", value).Build()
+		assert.Error(t, err, value)
+	}
+
+	// the grammar has no negative numbers
+	for _, value := range []interface{}{-1, int8(-1), int64(-100)} {
+		_, err := rpc.NewQuery().Gt("tx.height", value).Build()
+		assert.Error(t, err, "%v", value)
+	}
+	for _, value := range []interface{}{0, int32(7), uint(7), uint64(1) << 63} {
+		_, err := rpc.NewQuery().Gte("tx.height", value).Build()
+		assert.NoError(t, err, "%v", value)
+	}
+	_, err = rpc.NewQuery().Eq("price", 1.5).Build()
+	assert.Error(t, err, "floats are not supported")
+
+	at := time.Date(2019, 7, 1, 12, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	query, err = rpc.NewQuery().Gte("tx.time", at).Lt("tx.time", at.Add(time.Hour).UTC()).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "tx.time >= TIME 2019-07-01T12:30:00+08:00 AND tx.time < TIME 2019-07-01T05:30:00Z", query)
+}
+
+func TestQueryAnd(t *testing.T) {
+	heights := rpc.NewQuery().Gte("tx.height", 10).Lte("tx.height", 20)
+	query, err := rpc.NewQuery().EventType(tmtypes.EventTx).And(heights).And(nil).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "tm.event = 'Tx' AND tx.height >= 10 AND tx.height <= 20", query)
+
+	// the first invalid condition is reported, of either query
+	invalid := rpc.NewQuery().Eq("", "v")
+	_, err = rpc.NewQuery().EventType(tmtypes.EventTx).And(invalid).Build()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid query key")
+	}
+	_, err = rpc.NewQuery().Gt("tx.height", -1).And(invalid).Build()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "tx.height")
+	}
+
+	// the conditions after an invalid one are dropped
+	q := rpc.NewQuery().Eq("a", 1).Eq("b c", 2).Eq("d", 3)
+	assert.Equal(t, "a = 1", q.String())
+}
@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"sync"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+// TxEvent is a committed transaction delivered by SubscribeTx.
+type TxEvent struct {
+	Hash   cmn.HexBytes           `json:"hash"`
+	Height int64                  `json:"height"`
+	Index  uint32                 `json:"index"`
+	Tx     tx.StdTx               `json:"tx"`
+	Result abci.ResponseDeliverTx `json:"result"`
+	Tags   map[string]string      `json:"tags"`
+}
+
+// SubscribeNewBlock delivers the committed blocks until stop is called, which
+// closes the channel.
+func (c *HTTP) SubscribeNewBlock(outCapacity ...int) (<-chan tmtypes.EventDataNewBlock, func() error, error) {
+	out := make(chan tmtypes.EventDataNewBlock, eventCapacity(outCapacity))
+	stop, err := c.subscribeEvents(NewQuery().EventType(tmtypes.EventNewBlock), outCapacity, func() { close(out) },
+		func(event ctypes.ResultEvent, quit <-chan struct{}) {
+			if data, ok := event.Data.(tmtypes.EventDataNewBlock); ok {
+				select {
+				case out <- data:
+				case <-quit:
+				}
+			}
+		})
+	return out, stop, err
+}
+
+// SubscribeNewBlockHeader delivers the headers of the committed blocks until
+// stop is called, which closes the channel.
+func (c *HTTP) SubscribeNewBlockHeader(outCapacity ...int) (<-chan tmtypes.EventDataNewBlockHeader, func() error, error) {
+	out := make(chan tmtypes.EventDataNewBlockHeader, eventCapacity(outCapacity))
+	stop, err := c.subscribeEvents(NewQuery().EventType(tmtypes.EventNewBlockHeader), outCapacity, func() { close(out) },
+		func(event ctypes.ResultEvent, quit <-chan struct{}) {
+			if data, ok := event.Data.(tmtypes.EventDataNewBlockHeader); ok {
+				select {
+				case out <- data:
+				case <-quit:
+				}
+			}
+		})
+	return out, stop, err
+}
+
+// SubscribeValidatorSetUpdates delivers the changes of the validator set until
+// stop is called, which closes the channel.
+func (c *HTTP) SubscribeValidatorSetUpdates(outCapacity ...int) (<-chan tmtypes.EventDataValidatorSetUpdates, func() error, error) {
+	out := make(chan tmtypes.EventDataValidatorSetUpdates, eventCapacity(outCapacity))
+	stop, err := c.subscribeEvents(NewQuery().EventType(tmtypes.EventValidatorSetUpdates), outCapacity, func() { close(out) },
+		func(event ctypes.ResultEvent, quit <-chan struct{}) {
+			if data, ok := event.Data.(tmtypes.EventDataValidatorSetUpdates); ok {
+				select {
+				case out <- data:
+				case <-quit:
+				}
+			}
+		})
+	return out, stop, err
+}
+
+// SubscribeTx delivers the decoded committed transactions matching filter, all
+// of them when filter is nil, until stop is called, which closes the channel.
+// The transactions that can't be decoded are logged and skipped.
+func (c *HTTP) SubscribeTx(filter *Query, outCapacity ...int) (<-chan TxEvent, func() error, error) {
+	out := make(chan TxEvent, eventCapacity(outCapacity))
+	query := NewQuery().EventType(tmtypes.EventTx).And(filter)
+	stop, err := c.subscribeEvents(query, outCapacity, func() { close(out) },
+		func(event ctypes.ResultEvent, quit <-chan struct{}) {
+			data, ok := event.Data.(tmtypes.EventDataTx)
+			if !ok {
+				return
+			}
+			parsedTx, err := ParseTx(c.cdc, data.Tx)
+			if err != nil {
+				c.Logger.Error("failed to decode the transaction of the event", "height", data.Height, "index", data.Index, "err", err)
+				return
+			}
+			txEvent := TxEvent{
+				Hash:   data.Tx.Hash(),
+				Height: data.Height,
+				Index:  data.Index,
+				Tx:     parsedTx.(tx.StdTx),
+				Result: data.Result,
+				Tags:   event.Tags,
+			}
+			select {
+			case out <- txEvent:
+			case <-quit:
+			}
+		})
+	return out, stop, err
+}
+
+// subscribeEvents hands the events of query to deliver until stop is called,
+// then calls done.
+func (c *HTTP) subscribeEvents(q *Query, outCapacity []int, done func(), deliver func(event ctypes.ResultEvent, quit <-chan struct{})) (stop func() error, err error) {
+	query, err := q.Build()
+	if err != nil {
+		return nil, err
+	}
+	in, err := c.Subscribe(query, outCapacity...)
+	if err != nil {
+		return nil, err
+	}
+	quit := make(chan struct{})
+	go func() {
+		defer done()
+		for {
+			select {
+			case event := <-in:
+				deliver(event, quit)
+			case <-quit:
+				return
+			}
+		}
+	}()
+	// stop may be called more than once, only the first call unsubscribes
+	var once sync.Once
+	return func() error {
+		var err error
+		once.Do(func() {
+			close(quit)
+			err = c.Unsubscribe(query)
+		})
+		return err
+	}, nil
+}
+
+func eventCapacity(outCapacity []int) int {
+	if len(outCapacity) > 0 {
+		return outCapacity[0]
+	}
+	return 1
+}
@@ -0,0 +1,31 @@
+package rpc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/binance-chain/go-sdk/client/rpc"
+	ntypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+func TestSubscribeStopTwice(t *testing.T) {
+	s := newRPCServer(statusHandler)
+	defer s.Close()
+	c := rpc.NewRPCClient("tcp://"+s.host(), ntypes.TestNetwork)
+	defer c.Stop()
+
+	blocks, stop, err := c.SubscribeNewBlock()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, stop())
+	_, ok := <-blocks
+	assert.False(t, ok, "stop closes the channel")
+	assert.NoError(t, stop())
+
+	// the query can be subscribed again
+	_, stop, err = c.SubscribeNewBlock()
+	assert.NoError(t, err)
+	assert.NoError(t, stop())
+}
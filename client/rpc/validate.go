@@ -29,6 +29,7 @@ var (
 	LimitNegativeError                = fmt.Errorf("the limit can't be negative")
 	ExceedMaxUnConfirmedTxsNumError   = fmt.Errorf("the limit of unConfirmed tx exceed max limit %d ", maxUnConfirmedTxs)
 	HeightNegativeError               = fmt.Errorf("the height can't be negative")
+	HeightNotPositiveError            = fmt.Errorf("the height should be positive")
 	MaxMinHeightConflictError         = fmt.Errorf("the min height can't be larger than max height")
 	HashLengthError                   = fmt.Errorf("the length of hash is not 32")
 	ExceedABCIQueryStrLengthError     = fmt.Errorf("the query string exceed max length %d ", maxABCIPathLength)
@@ -86,6 +87,13 @@ func ValidateHeight(height *int64) error {
 	return nil
 }
 
+func ValidatePinnedHeight(height int64) error {
+	if height <= 0 {
+		return HeightNotPositiveError
+	}
+	return nil
+}
+
 func ValidateHash(hash []byte) error {
 	if len(hash) != sha256.Size {
 		return HashLengthError
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/rpc/client"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/binance-chain/go-sdk/common/proof"
+	"github.com/binance-chain/go-sdk/common/types"
+	sdktypes "github.com/binance-chain/go-sdk/types"
+)
+
+// ErrProofVerification is returned, wrapped, when the data of the node does
+// not match its proof.
+var ErrProofVerification = errors.New("proof verification failed")
+
+// ErrNoLightClient is returned by the verified queries of a client without a
+// light client: the app hash of a header the node chose proves nothing.
+var ErrNoLightClient = errors.New("verified queries need a light client, see SetLightClient")
+
+var proofRuntime = proof.DefaultProofRuntime()
+
+// QueryStoreVerified is QueryStore checking the Merkle proof of the answer
+// against the app hash of the block header. The state is read at the height
+// before the latest block, whose header holds the app hash of that state.
+// The header is verified by the light client of the client, see
+// SetLightClient, ErrNoLightClient is returned when none is set.
+func (c *HTTP) QueryStoreVerified(key cmn.HexBytes, storeName string) ([]byte, error) {
+	if c.lightClient == nil {
+		return nil, ErrNoLightClient
+	}
+	latest, err := c.trustedHeader(nil)
+	if err != nil {
+		return nil, err
+	}
+	height := latest.Height - 1
+	if height < 1 {
+		return nil, fmt.Errorf("no state to verify at height %d", latest.Height)
+	}
+	path := fmt.Sprintf("/store/%s/%s", storeName, "key")
+	result, err := c.ABCIQueryWithOptions(path, key, client.ABCIQueryOptions{Height: height, Prove: true})
+	if err != nil {
+		return nil, err
+	}
+	resp := result.Response
+	if !resp.IsOK() {
+		return nil, errors.New(resp.Log)
+	}
+	header := latest
+	if resp.Height != height {
+		next := resp.Height + 1
+		if header, err = c.trustedHeader(&next); err != nil {
+			return nil, err
+		}
+	}
+	if err := proof.VerifyStoreValue(proofRuntime, resp.Proof, header.AppHash, storeName, key, resp.Value); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrProofVerification, err.Error())
+	}
+	return resp.Value, nil
+}
+
+// GetVerifiedCommitAccount is GetCommitAccount with the account checked
+// against the app hash, see QueryStoreVerified.
+func (c *HTTP) GetVerifiedCommitAccount(addr types.AccAddress) (acc types.Account, err error) {
+	bz, err := c.QueryStoreVerified(accountStoreKey(addr), AccountStoreName)
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return nil, nil
+	}
+	err = c.cdc.UnmarshalBinaryBare(bz, &acc)
+	if err != nil {
+		return nil, err
+	}
+	return acc, err
+}
+
+// GetVerifiedTokenInfo is GetTokenInfo with the token checked against the
+// app hash, see QueryStoreVerified.
+func (c *HTTP) GetVerifiedTokenInfo(symbol string) (*types.Token, error) {
+	if err := ValidateSymbol(symbol); err != nil {
+		return nil, err
+	}
+	bz, err := c.QueryStoreVerified(tokenStoreKey(symbol), TokenStoreName)
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return nil, fmt.Errorf("token %s not found", symbol)
+	}
+	token := new(types.Token)
+	err = c.cdc.UnmarshalBinaryBare(bz, token)
+	return token, err
+}
+
+// trustedHeader returns the header at height verified by the light client,
+// the latest one when height is nil.
+func (c *HTTP) trustedHeader(height *int64) (*tmtypes.Header, error) {
+	shdr, err := c.lightClient.SignedHeader(height)
+	if err != nil {
+		return nil, err
+	}
+	return shdr.Header, nil
+}
+
+// accountStoreKey is the key of an account in the account store.
+func accountStoreKey(addr types.AccAddress) []byte {
+	return append([]byte("account:"), addr.Bytes()...)
+}
+
+// tokenStoreKey is the key of a token in the token store, the native token is
+// stored upper case.
+func tokenStoreKey(symbol string) []byte {
+	if strings.EqualFold(symbol, sdktypes.NativeSymbol) {
+		return []byte(strings.ToUpper(symbol))
+	}
+	return []byte(symbol)
+}
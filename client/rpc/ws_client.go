@@ -130,7 +130,10 @@ func (c *WSEvents) IsActive() bool {
 // Channel is never closed to prevent clients from seeing an erroneus event.
 func (w *WSEvents) Subscribe(query string,
 	outCapacity ...int) (out chan ctypes.ResultEvent, err error) {
-	if _, ok := w.subscriptionsIdMap[query]; ok {
+	w.mtx.Lock()
+	_, ok := w.subscriptionsIdMap[query]
+	w.mtx.Unlock()
+	if ok {
 		return nil, errors.New("already subscribe")
 	}
 
@@ -467,8 +470,14 @@ func (w *WSEvents) NewContext() (context.Context, context.CancelFunc) {
 // After being reconnected, it is necessary to redo subscription to server
 // otherwise no data will be automatically received.
 func (w *WSEvents) redoSubscriptionsAfter() {
-
+	w.mtx.Lock()
+	subscriptions := make(map[string]rpctypes.JSONRPCStringID, len(w.subscriptionsIdMap))
 	for q, id := range w.subscriptionsIdMap {
+		subscriptions[q] = id
+	}
+	w.mtx.Unlock()
+
+	for q, id := range subscriptions {
 		ctx, _ := context.WithTimeout(context.Background(), w.timeout)
 		err := w.getWsClient().Subscribe(ctx, id, q)
 		if err != nil {
@@ -964,13 +973,11 @@ func makeHTTPDialer(remoteAddr string) (string, string, func(string, string) (ne
 		}
 	}
 
-	// accept http as an alias for tcp and set the client protocol
+	// accept http and ws as aliases for tcp and set the client protocol
 	switch protocol {
-	case protoHTTP, protoHTTPS:
+	case protoHTTP, protoHTTPS, protoWS, protoWSS:
 		clientProtocol = protocol
 		protocol = protoTCP
-	case protoWS, protoWSS:
-		clientProtocol = protocol
 	}
 
 	// replace / with . for http requests (kvstore domain)
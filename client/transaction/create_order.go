@@ -27,15 +27,25 @@ func (c *client) CreateOrder(baseAssetSymbol, quoteAssetSymbol string, op int8,
 		price,
 		quantity,
 	)
-	commit, err := c.broadcastMsg(newOrderMsg, sync, options...)
+	signedTx, err := c.signMsg(newOrderMsg, options...)
 	if err != nil {
 		return nil, err
 	}
+	commit, err := c.broadcastSigned(signedTx, sync)
+	if err != nil {
+		return nil, err
+	}
+	if c.dryRun != nil {
+		// the node reports the id generated from the sequence when signing
+		return &CreateOrderResult{*commit, signedTx.SignMsg.Msgs[0].(msg.CreateOrderMsg).ID}, nil
+	}
 	type commitData struct {
 		OrderId string `json:"order_id"`
 	}
 	var cdata commitData
-	if sync {
+	// the node rejects an order in the sync mode with an empty data and the
+	// reason in the log, only an accepted one carries its order id
+	if commit.Ok && sync {
 		err = json.Unmarshal([]byte(commit.Data), &cdata)
 		if err != nil {
 			return nil, err
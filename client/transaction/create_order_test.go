@@ -0,0 +1,22 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/binance-chain/go-sdk/types/msg"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+func TestCreateOrderRejected(t *testing.T) {
+	c, b, _ := newPayoutTestClient(t, 10)
+	b.post = func([]byte) ([]tx.TxCommitResult, error) {
+		return []tx.TxCommitResult{{Ok: false, Log: "insufficient funds"}}, nil
+	}
+	result, err := c.CreateOrder("NNB-0AD", "BNB", msg.OrderSide.BUY, 100000000, 100000000, true)
+	assert.NoError(t, err)
+	assert.False(t, result.Ok)
+	assert.Equal(t, "insufficient funds", result.Log)
+	assert.Empty(t, result.OrderId)
+}
@@ -0,0 +1,41 @@
+package transaction
+
+import (
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+// SignedTx is a transaction built, validated and signed by the client.
+type SignedTx struct {
+	SignMsg tx.StdSignMsg `json:"sign_msg"`
+	// HexTx is the hex encoded transaction, as posted to the API.
+	HexTx  string `json:"hex_tx"`
+	TxHash string `json:"tx_hash"`
+}
+
+// DryRun returns a view of the client whose methods build, validate and sign
+// the transactions without posting them, and hand them to signed. Their
+// results are Ok and hold the locally computed hash, CreateOrder also the id of
+// the order. The account is still queried for its sequence unless
+// WithAcNumAndSequence is given. SendPayouts signs every pending batch with
+// consecutive sequences and leaves the report and its file untouched.
+func (c *client) DryRun(signed func(signedTx SignedTx)) TransactionClient {
+	if signed == nil {
+		signed = func(SignedTx) {}
+	}
+	dryRun := *c
+	dryRun.dryRun = signed
+	return &dryRun
+}
+
+// sign signs signMsg with the key manager.
+func (c *client) sign(signMsg tx.StdSignMsg) (*SignedTx, error) {
+	hexTx, err := c.keyManager.Sign(signMsg)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := tx.HexTxHash(hexTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedTx{SignMsg: signMsg, HexTx: string(hexTx), TxHash: txHash}, nil
+}
@@ -0,0 +1,95 @@
+package transaction
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/msg"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+func TestDryRunCreateOrder(t *testing.T) {
+	c, b, _ := newPayoutTestClient(t, 10)
+	var signed []SignedTx
+	dryRun := c.DryRun(func(signedTx SignedTx) {
+		signed = append(signed, signedTx)
+	})
+
+	for _, sync := range []bool{true, false} {
+		signed = nil
+		result, err := dryRun.CreateOrder("NNB-0AD", "BNB", msg.OrderSide.BUY, 100000000, 100000000, sync, WithMemo("dry"))
+		assert.NoError(t, err)
+		assert.True(t, result.Ok)
+		assert.Equal(t, msg.GenerateOrderID(11, c.keyManager.GetAddr()), result.OrderId)
+		if assert.Len(t, signed, 1) {
+			assert.Equal(t, int64(10), signed[0].SignMsg.Sequence)
+			assert.Equal(t, int64(7), signed[0].SignMsg.AccountNumber)
+			assert.Equal(t, "dry", signed[0].SignMsg.Memo)
+			hash, err := tx.HexTxHash([]byte(signed[0].HexTx))
+			assert.NoError(t, err)
+			assert.Equal(t, hash, signed[0].TxHash)
+			assert.Equal(t, hash, result.Hash)
+		}
+	}
+
+	// an invalid transaction fails as it would without dry run
+	_, err := dryRun.CreateOrder("NNB-0AD", "BNB", msg.OrderSide.BUY, 0, 100000000, true)
+	assert.Error(t, err)
+	assert.Empty(t, b.posted)
+
+	// the client itself still posts
+	result, err := c.SendToken([]msg.Transfer{{ToAddr: c.keyManager.GetAddr(), Coins: types.Coins{{Denom: "BNB", Amount: 1}}}}, true)
+	assert.NoError(t, err)
+	assert.Len(t, b.posted, 1)
+	hash, err := tx.HexTxHash(b.posted[0])
+	assert.NoError(t, err)
+	assert.Equal(t, hash, result.Hash)
+}
+
+func TestDryRunSendPayouts(t *testing.T) {
+	c, b, _ := newPayoutTestClient(t, 10)
+	report := interruptedReport(t, c)
+	before, err := json.Marshal(report)
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "payout")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "report.json")
+
+	var signed []SignedTx
+	err = c.DryRun(func(signedTx SignedTx) {
+		signed = append(signed, signedTx)
+	}).SendPayouts(report, file, true)
+	assert.NoError(t, err)
+
+	// only the pending batch is signed, the broadcasting one is not resolved
+	if assert.Len(t, signed, 1) {
+		assert.Equal(t, int64(10), signed[0].SignMsg.Sequence)
+		assert.Len(t, signed[0].SignMsg.Msgs[0].(msg.SendMsg).Outputs, 2)
+	}
+	assert.Empty(t, b.posted)
+	after, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "the report is not changed")
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err), "the report is not saved")
+
+	// the pending batches get consecutive sequences
+	report = PlanPayouts(payoutRows(t, 5), WithMaxOutputs(2))
+	signed = nil
+	assert.NoError(t, c.DryRun(func(signedTx SignedTx) {
+		signed = append(signed, signedTx)
+	}).SendPayouts(report, "", true))
+	if assert.Len(t, signed, 3) {
+		for i, signedTx := range signed {
+			assert.Equal(t, int64(10+i), signedTx.SignMsg.Sequence)
+		}
+	}
+	assert.Equal(t, 5, report.Count(PayoutPending))
+}
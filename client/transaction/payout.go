@@ -0,0 +1,420 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/msg"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+const (
+	DefaultPayoutMaxOutputs = 100
+	DefaultPayoutMaxBytes   = 64 * 1024
+
+	// rough size of everything in a signed send tx but its outputs:
+	// amino prefixes, the input, the signature and the memo.
+	payoutTxOverhead = 256 + tx.MaxMemoCharacters
+
+	// lower-cased message of the mempool error returned for a tx it already holds
+	txInCacheMessage = "tx already exists in cache"
+)
+
+type PayoutStatus string
+
+const (
+	// PayoutPending rows have not been broadcast yet.
+	PayoutPending PayoutStatus = "pending"
+	// PayoutInvalid rows have a bad address or bad coins and are never sent.
+	PayoutInvalid PayoutStatus = "invalid"
+	// PayoutBroadcasting rows belong to a signed tx whose broadcast result is unknown,
+	// it is resolved by querying the tx hash when the payout is resumed. The rows
+	// stay in this status until the chain tells whether the tx was included.
+	PayoutBroadcasting PayoutStatus = "broadcasting"
+	// PayoutSent rows were accepted by the chain, and committed when sent with sync.
+	PayoutSent PayoutStatus = "sent"
+	// PayoutFailed rows belong to a tx rejected by the chain.
+	PayoutFailed PayoutStatus = "failed"
+)
+
+// PayoutRow is one line of a payout list.
+type PayoutRow struct {
+	Address string      `json:"address"`
+	Coins   types.Coins `json:"coins"`
+}
+
+// PayoutRecord is the reconciliation state of one payout row.
+type PayoutRecord struct {
+	PayoutRow
+	Batch  int          `json:"batch"` // -1 for invalid rows
+	Status PayoutStatus `json:"status"`
+	TxHash string       `json:"tx_hash,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// PayoutBatch is a group of rows sent in the same transaction.
+type PayoutBatch struct {
+	Rows     []int        `json:"rows"`
+	Status   PayoutStatus `json:"status"`
+	Sequence int64        `json:"sequence"`
+	TxHash   string       `json:"tx_hash,omitempty"`
+	HexTx    string       `json:"hex_tx,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// PayoutReport holds the plan and the result of a payout. It is saved after
+// every step so that an interrupted payout can be resumed from the saved file.
+type PayoutReport struct {
+	Records []PayoutRecord `json:"records"`
+	Batches []PayoutBatch  `json:"batches"`
+}
+
+type payoutPlanner struct {
+	maxOutputs int
+	maxBytes   int
+}
+
+type PayoutOption func(*payoutPlanner)
+
+// WithMaxOutputs bounds the number of outputs of every payout transaction.
+func WithMaxOutputs(maxOutputs int) PayoutOption {
+	return func(p *payoutPlanner) {
+		if maxOutputs > 0 {
+			p.maxOutputs = maxOutputs
+		}
+	}
+}
+
+// WithMaxTxBytes bounds the estimated encoded size of every payout transaction.
+func WithMaxTxBytes(maxBytes int) PayoutOption {
+	return func(p *payoutPlanner) {
+		if maxBytes > payoutTxOverhead {
+			p.maxBytes = maxBytes
+		}
+	}
+}
+
+// PlanPayouts validates every row and packs the valid ones into as few
+// transactions as the output and size limits allow. Invalid rows are kept in
+// the report with the reason, they don't prevent the others to be paid.
+func PlanPayouts(rows []PayoutRow, options ...PayoutOption) *PayoutReport {
+	planner := payoutPlanner{maxOutputs: DefaultPayoutMaxOutputs, maxBytes: DefaultPayoutMaxBytes}
+	for _, option := range options {
+		option(&planner)
+	}
+
+	report := &PayoutReport{Records: make([]PayoutRecord, len(rows))}
+	var current *PayoutBatch
+	var currentBytes int
+	for i, row := range rows {
+		// Sort works in place, copy the coins to leave the caller's rows untouched
+		coins := append(types.Coins(nil), row.Coins...).Sort()
+		record := PayoutRecord{PayoutRow: PayoutRow{Address: row.Address, Coins: coins}, Batch: -1}
+		output, err := payoutOutput(record.PayoutRow)
+		if err != nil {
+			record.Status = PayoutInvalid
+			record.Error = err.Error()
+			report.Records[i] = record
+			continue
+		}
+		size := outputSize(output)
+		if current == nil || len(current.Rows) >= planner.maxOutputs || currentBytes+size > planner.maxBytes {
+			report.Batches = append(report.Batches, PayoutBatch{Status: PayoutPending})
+			current = &report.Batches[len(report.Batches)-1]
+			currentBytes = payoutTxOverhead
+		}
+		current.Rows = append(current.Rows, i)
+		currentBytes += size
+		record.Batch = len(report.Batches) - 1
+		record.Status = PayoutPending
+		report.Records[i] = record
+	}
+	return report
+}
+
+func payoutOutput(row PayoutRow) (msg.Output, error) {
+	addr, err := types.AccAddressFromBech32(row.Address)
+	if err != nil {
+		return msg.Output{}, err
+	}
+	output := msg.NewOutput(addr, row.Coins)
+	if err := output.ValidateBasic(); err != nil {
+		return msg.Output{}, err
+	}
+	return output, nil
+}
+
+func outputSize(output msg.Output) int {
+	bz, err := msg.MsgCdc.MarshalBinaryLengthPrefixed(output)
+	if err != nil {
+		return 0
+	}
+	return len(bz)
+}
+
+// LoadPayoutReport reads a report previously saved by SendPayouts.
+func LoadPayoutReport(file string) (*PayoutReport, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var report PayoutReport
+	if err := json.Unmarshal(bz, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Save writes the report to file. The file is replaced atomically so that a
+// crash never leaves a truncated report behind.
+func (r *PayoutReport) Save(file string) error {
+	bz, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile := file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, bz, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
+
+// Count returns the number of rows in the given status.
+func (r *PayoutReport) Count(status PayoutStatus) int {
+	count := 0
+	for _, record := range r.Records {
+		if record.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Done returns whether every row reached a final status.
+func (r *PayoutReport) Done() bool {
+	return r.Count(PayoutPending) == 0 && r.Count(PayoutBroadcasting) == 0
+}
+
+// setBatchTx records the signed transaction of a batch, to resolve its
+// outcome if the broadcast is interrupted.
+func (r *PayoutReport) setBatchTx(idx int, signedTx *SignedTx) {
+	batch := &r.Batches[idx]
+	batch.Sequence = signedTx.SignMsg.Sequence
+	batch.HexTx = signedTx.HexTx
+	batch.TxHash = signedTx.TxHash
+}
+
+func (r *PayoutReport) setBatchStatus(idx int, status PayoutStatus, errMsg string) {
+	batch := &r.Batches[idx]
+	batch.Status = status
+	batch.Error = errMsg
+	for _, row := range batch.Rows {
+		r.Records[row].Status = status
+		r.Records[row].TxHash = batch.TxHash
+		r.Records[row].Error = errMsg
+	}
+}
+
+// SendPayouts broadcasts every pending batch of the report, one transaction per
+// batch with consecutive sequences. The report is saved to reportFile after
+// every step when reportFile is not empty. Calling it again with a loaded
+// report resumes the payout: batches of unknown outcome are first resolved
+// through their tx hash, and rebroadcast with the same signed bytes if the
+// chain does not know them, so that no row is ever paid twice. When the
+// outcome of a batch is still unknown after that, the batch is left
+// broadcasting and an error is returned before any other batch is sent.
+func (c *client) SendPayouts(report *PayoutReport, reportFile string, sync bool, options ...Option) error {
+	if c.dryRun != nil {
+		return c.dryRunPayouts(report, options...)
+	}
+	save := func() error {
+		if reportFile == "" {
+			return nil
+		}
+		return report.Save(reportFile)
+	}
+	if err := save(); err != nil {
+		return err
+	}
+
+	for i := range report.Batches {
+		if report.Batches[i].Status == PayoutBroadcasting {
+			resolveErr := c.resolvePayoutBatch(report, i, sync)
+			if err := save(); err != nil {
+				return err
+			}
+			if resolveErr != nil {
+				return resolveErr
+			}
+		}
+	}
+
+	fromAddr := c.keyManager.GetAddr()
+	acc, err := c.queryClient.GetAccount(fromAddr.String())
+	if err != nil {
+		return err
+	}
+	sequence := acc.Sequence
+	for i := range report.Batches {
+		if report.Batches[i].Status != PayoutPending {
+			continue
+		}
+		signedTx, err := c.signPayoutBatch(report, i, acc.Number, sequence, options...)
+		if err != nil {
+			report.setBatchStatus(i, PayoutFailed, err.Error())
+			if err := save(); err != nil {
+				return err
+			}
+			continue
+		}
+		report.setBatchTx(i, signedTx)
+		report.setBatchStatus(i, PayoutBroadcasting, "")
+		// the signed tx must be on disk before it is broadcast
+		if err := save(); err != nil {
+			return err
+		}
+		commit, err := c.postTx([]byte(signedTx.HexTx), sync)
+		if err != nil {
+			// the outcome is unknown, stop here and let the next run resolve it
+			return fmt.Errorf("failed to broadcast payout batch %d: %s", i, err.Error())
+		}
+		if commit.Ok {
+			report.setBatchStatus(i, PayoutSent, "")
+			sequence++
+		} else {
+			report.setBatchStatus(i, PayoutFailed, commit.Log)
+			// a tx failing in DeliverTx still consumes its sequence
+			if sync {
+				if acc, err = c.queryClient.GetAccount(fromAddr.String()); err != nil {
+					return err
+				}
+				sequence = acc.Sequence
+			}
+		}
+		if err := save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dryRunPayouts signs the pending batches of report for the dry run, without
+// changing the report.
+func (c *client) dryRunPayouts(report *PayoutReport, options ...Option) error {
+	acc, err := c.queryClient.GetAccount(c.keyManager.GetAddr().String())
+	if err != nil {
+		return err
+	}
+	sequence := acc.Sequence
+	for i := range report.Batches {
+		if report.Batches[i].Status != PayoutPending {
+			continue
+		}
+		signedTx, err := c.signPayoutBatch(report, i, acc.Number, sequence, options...)
+		if err != nil {
+			return fmt.Errorf("failed to sign payout batch %d: %s", i, err.Error())
+		}
+		c.dryRun(*signedTx)
+		sequence++
+	}
+	return nil
+}
+
+func (c *client) signPayoutBatch(report *PayoutReport, idx int, accountNumber, sequence int64, options ...Option) (*SignedTx, error) {
+	batch := &report.Batches[idx]
+	fromCoins := types.Coins{}
+	transfers := make([]msg.Transfer, 0, len(batch.Rows))
+	for _, row := range batch.Rows {
+		output, err := payoutOutput(report.Records[row].PayoutRow)
+		if err != nil {
+			return nil, err
+		}
+		fromCoins = fromCoins.Plus(output.Coins)
+		transfers = append(transfers, msg.Transfer{ToAddr: output.Address, Coins: output.Coins})
+	}
+	sendMsg := msg.CreateSendMsg(c.keyManager.GetAddr(), fromCoins, transfers)
+	if err := sendMsg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	signMsg := &tx.StdSignMsg{
+		ChainID: c.chainId,
+		Msgs:    []msg.Msg{sendMsg},
+		Source:  tx.Source,
+	}
+	for _, op := range options {
+		signMsg = op(signMsg)
+	}
+	signMsg.AccountNumber = accountNumber
+	signMsg.Sequence = sequence
+	if err := tx.ValidateMemo(signMsg.Memo); err != nil {
+		return nil, err
+	}
+	return c.sign(*signMsg)
+}
+
+// resolvePayoutBatch settles a batch whose broadcast outcome was lost. The
+// batch is only marked sent or failed on a definitive answer of the chain,
+// otherwise it stays broadcasting and an error is returned: the tx may still
+// be included, and paying its rows again could pay them twice.
+func (c *client) resolvePayoutBatch(report *PayoutReport, idx int, sync bool) error {
+	batch := &report.Batches[idx]
+	if result, err := c.basicClient.GetTx(batch.TxHash); err == nil && result.Hash != "" {
+		if result.Code == tx.CodeOk {
+			report.setBatchStatus(idx, PayoutSent, "")
+		} else {
+			report.setBatchStatus(idx, PayoutFailed, result.Log)
+		}
+		return nil
+	}
+	// the chain does not know the tx, rebroadcasting the same signed bytes
+	// can't pay anyone twice since they carry the same sequence
+	commit, err := c.postTx([]byte(batch.HexTx), sync)
+	if err != nil {
+		if isTxInCache(err.Error()) {
+			return fmt.Errorf("payout batch %d is still pending in the mempool", idx)
+		}
+		return fmt.Errorf("outcome of payout batch %d is unknown: %s", idx, err.Error())
+	}
+	if commit.Ok {
+		report.setBatchStatus(idx, PayoutSent, "")
+		return nil
+	}
+	if isTxInCache(commit.Log) {
+		return fmt.Errorf("payout batch %d is still pending in the mempool", idx)
+	}
+	// The tx is rejected, but it may be the rejection of a copy of a tx that
+	// was committed and not indexed yet. It is only final if its sequence is
+	// still unused.
+	acc, err := c.queryClient.GetAccount(c.keyManager.GetAddr().String())
+	if err != nil {
+		return fmt.Errorf("outcome of payout batch %d is unknown: %s", idx, err.Error())
+	}
+	if acc.Sequence > batch.Sequence {
+		return fmt.Errorf("outcome of payout batch %d is unknown: sequence %d is used but tx %s is not found", idx, batch.Sequence, batch.TxHash)
+	}
+	report.setBatchStatus(idx, PayoutFailed, commit.Log)
+	return nil
+}
+
+func isTxInCache(message string) bool {
+	return strings.Contains(strings.ToLower(message), txInCacheMessage)
+}
+
+func (c *client) postTx(hexTx []byte, sync bool) (*tx.TxCommitResult, error) {
+	param := map[string]string{}
+	if sync {
+		param["sync"] = "true"
+	}
+	commits, err := c.basicClient.PostTx(hexTx, param)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) < 1 {
+		return nil, fmt.Errorf("Len of tx Commit result is less than 1 ")
+	}
+	return &commits[0], nil
+}
@@ -0,0 +1,221 @@
+package transaction
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/binance-chain/go-sdk/client/basic"
+	"github.com/binance-chain/go-sdk/client/query"
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/keys"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+type fakeBasicClient struct {
+	basic.BasicClient
+
+	txs    map[string]*tx.TxResult
+	post   func(hexTx []byte) ([]tx.TxCommitResult, error)
+	posted [][]byte
+}
+
+func (c *fakeBasicClient) GetTx(txHash string) (*tx.TxResult, error) {
+	if result, ok := c.txs[txHash]; ok {
+		return result, nil
+	}
+	return nil, errors.New("bad response, status code 404, response: tx not found")
+}
+
+func (c *fakeBasicClient) PostTx(hexTx []byte, param map[string]string) ([]tx.TxCommitResult, error) {
+	c.posted = append(c.posted, hexTx)
+	if c.post != nil {
+		return c.post(hexTx)
+	}
+	hash, err := tx.HexTxHash(hexTx)
+	if err != nil {
+		return nil, err
+	}
+	return []tx.TxCommitResult{{Ok: true, Hash: hash}}, nil
+}
+
+type fakeQueryClient struct {
+	query.QueryClient
+
+	account types.BalanceAccount
+}
+
+func (c *fakeQueryClient) GetAccount(string) (*types.BalanceAccount, error) {
+	acc := c.account
+	return &acc, nil
+}
+
+func newPayoutTestClient(t *testing.T, sequence int64) (*client, *fakeBasicClient, *fakeQueryClient) {
+	km, err := keys.NewKeyManager()
+	assert.NoError(t, err)
+	b := &fakeBasicClient{txs: map[string]*tx.TxResult{}}
+	q := &fakeQueryClient{account: types.BalanceAccount{Number: 7, Sequence: sequence}}
+	c := NewClient("test-chain", km, q, b).(*client)
+	return c, b, q
+}
+
+func payoutRows(t *testing.T, n int) []PayoutRow {
+	rows := make([]PayoutRow, n)
+	for i := range rows {
+		km, err := keys.NewKeyManager()
+		assert.NoError(t, err)
+		rows[i] = PayoutRow{Address: km.GetAddr().String(), Coins: types.Coins{{Denom: "BNB", Amount: int64(i + 1)}}}
+	}
+	return rows
+}
+
+func TestPlanPayouts(t *testing.T) {
+	rows := payoutRows(t, 5)
+	rows = append(rows,
+		PayoutRow{Address: "bnb1invalid", Coins: types.Coins{{Denom: "BNB", Amount: 1}}},
+		PayoutRow{Address: rows[0].Address, Coins: types.Coins{{Denom: "BNB", Amount: 0}}},
+		PayoutRow{Address: rows[1].Address, Coins: types.Coins{{Denom: "XYZ-000", Amount: 2}, {Denom: "BNB", Amount: 1}}},
+	)
+
+	report := PlanPayouts(rows, WithMaxOutputs(2))
+	assert.Len(t, report.Records, 8)
+	assert.Len(t, report.Batches, 3)
+	assert.Equal(t, []int{0, 1}, report.Batches[0].Rows)
+	assert.Equal(t, []int{2, 3}, report.Batches[1].Rows)
+	assert.Equal(t, []int{4, 7}, report.Batches[2].Rows)
+	assert.Equal(t, 6, report.Count(PayoutPending))
+	assert.Equal(t, 2, report.Count(PayoutInvalid))
+	assert.Equal(t, -1, report.Records[5].Batch)
+	assert.NotEmpty(t, report.Records[5].Error)
+	assert.Equal(t, -1, report.Records[6].Batch)
+	assert.Equal(t, 2, report.Records[7].Batch)
+	assert.False(t, report.Done())
+
+	// the coins of the report are sorted, the caller's rows are not touched
+	assert.Equal(t, "BNB", report.Records[7].Coins[0].Denom)
+	assert.Equal(t, "XYZ-000", rows[7].Coins[0].Denom)
+}
+
+func TestPlanPayoutsMaxBytes(t *testing.T) {
+	rows := payoutRows(t, 10)
+	output, err := payoutOutput(rows[0])
+	assert.NoError(t, err)
+	size := outputSize(output)
+
+	report := PlanPayouts(rows, WithMaxTxBytes(payoutTxOverhead+3*size))
+	for _, batch := range report.Batches {
+		assert.True(t, len(batch.Rows) <= 3)
+	}
+	assert.Len(t, report.Batches, 4)
+}
+
+func TestSendPayouts(t *testing.T) {
+	c, b, _ := newPayoutTestClient(t, 10)
+	report := PlanPayouts(payoutRows(t, 5), WithMaxOutputs(2))
+	dir, err := ioutil.TempDir("", "payout")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "report.json")
+
+	assert.NoError(t, c.SendPayouts(report, file, true))
+	assert.True(t, report.Done())
+	assert.Equal(t, 5, report.Count(PayoutSent))
+	assert.Len(t, b.posted, 3)
+	for i, batch := range report.Batches {
+		assert.Equal(t, int64(10+i), batch.Sequence)
+		assert.NotEmpty(t, batch.TxHash)
+	}
+
+	saved, err := LoadPayoutReport(file)
+	assert.NoError(t, err)
+	assert.Equal(t, report, saved)
+}
+
+// interruptedReport returns a report whose first batch was signed and left
+// broadcasting, as after a crash during its broadcast.
+func interruptedReport(t *testing.T, c *client) *PayoutReport {
+	report := PlanPayouts(payoutRows(t, 4), WithMaxOutputs(2))
+	signedTx, err := c.signPayoutBatch(report, 0, 7, 10)
+	assert.NoError(t, err)
+	report.setBatchTx(0, signedTx)
+	report.setBatchStatus(0, PayoutBroadcasting, "")
+	return report
+}
+
+func TestResumePayoutsCommitted(t *testing.T) {
+	c, b, q := newPayoutTestClient(t, 11)
+	report := interruptedReport(t, c)
+	b.txs[report.Batches[0].TxHash] = &tx.TxResult{Hash: report.Batches[0].TxHash, Code: tx.CodeOk}
+
+	assert.NoError(t, c.SendPayouts(report, "", true))
+	assert.True(t, report.Done())
+	assert.Equal(t, 4, report.Count(PayoutSent))
+	// only the second batch is posted, with the next sequence
+	assert.Len(t, b.posted, 1)
+	assert.Equal(t, q.account.Sequence, report.Batches[1].Sequence)
+}
+
+func TestResumePayoutsRebroadcast(t *testing.T) {
+	c, b, _ := newPayoutTestClient(t, 10)
+	report := interruptedReport(t, c)
+	hexTx := report.Batches[0].HexTx
+
+	assert.NoError(t, c.SendPayouts(report, "", true))
+	assert.True(t, report.Done())
+	assert.Len(t, b.posted, 2)
+	// the lost tx is rebroadcast with the very same bytes
+	assert.Equal(t, hexTx, string(b.posted[0]))
+}
+
+func TestResumePayoutsUnknownOutcome(t *testing.T) {
+	for name, post := range map[string]func([]byte) ([]tx.TxCommitResult, error){
+		"transport error": func([]byte) ([]tx.TxCommitResult, error) {
+			return nil, errors.New("connection reset by peer")
+		},
+		"in mempool error": func([]byte) ([]tx.TxCommitResult, error) {
+			return nil, errors.New("bad response, status code 500, response: Tx already exists in cache")
+		},
+		"in mempool result": func([]byte) ([]tx.TxCommitResult, error) {
+			return []tx.TxCommitResult{{Ok: false, Log: "Tx already exists in cache"}}, nil
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, b, _ := newPayoutTestClient(t, 10)
+			b.post = post
+			report := interruptedReport(t, c)
+
+			assert.Error(t, c.SendPayouts(report, "", true))
+			assert.Equal(t, PayoutBroadcasting, report.Batches[0].Status)
+			assert.Equal(t, 2, report.Count(PayoutBroadcasting))
+			// nothing else is sent while the first batch may still be included
+			assert.Equal(t, PayoutPending, report.Batches[1].Status)
+			assert.Len(t, b.posted, 1)
+		})
+	}
+}
+
+func TestResumePayoutsRejected(t *testing.T) {
+	rejected := func([]byte) ([]tx.TxCommitResult, error) {
+		return []tx.TxCommitResult{{Ok: false, Log: "insufficient funds"}}, nil
+	}
+
+	// the sequence of the lost tx is unused: the rejection is final
+	c, b, _ := newPayoutTestClient(t, 10)
+	b.post = rejected
+	report := interruptedReport(t, c)
+	assert.NoError(t, c.SendPayouts(report, "", true))
+	assert.Equal(t, PayoutFailed, report.Batches[0].Status)
+	assert.Equal(t, "insufficient funds", report.Records[0].Error)
+
+	// the sequence is used: the tx may be committed but not indexed yet
+	c, b, _ = newPayoutTestClient(t, 11)
+	b.post = rejected
+	report = interruptedReport(t, c)
+	assert.Error(t, c.SendPayouts(report, "", true))
+	assert.Equal(t, PayoutBroadcasting, report.Batches[0].Status)
+	assert.Equal(t, PayoutPending, report.Batches[1].Status)
+}
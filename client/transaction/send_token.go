@@ -1,6 +1,8 @@
 package transaction
 
 import (
+	"fmt"
+
 	"github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/go-sdk/types/msg"
 	"github.com/binance-chain/go-sdk/types/tx"
@@ -11,6 +13,11 @@ type SendTokenResult struct {
 }
 
 func (c *client) SendToken(transfers []msg.Transfer, sync bool, options ...Option) (*SendTokenResult, error) {
+	if c.memoCheck {
+		if err := c.checkRecipientsMemo(transfers, options...); err != nil {
+			return nil, err
+		}
+	}
 	fromAddr := c.keyManager.GetAddr()
 	fromCoins := types.Coins{}
 	for _, t := range transfers {
@@ -25,3 +32,31 @@ func (c *client) SendToken(transfers []msg.Transfer, sync bool, options ...Optio
 	return &SendTokenResult{*commit}, err
 
 }
+
+// checkRecipientsMemo refuses transfers without memo to accounts that set the
+// TransferMemoCheckerFlag, the chain would reject them after broadcast anyway.
+func (c *client) checkRecipientsMemo(transfers []msg.Transfer, options ...Option) error {
+	signMsg := &tx.StdSignMsg{}
+	for _, op := range options {
+		signMsg = op(signMsg)
+	}
+	if signMsg.Memo != "" {
+		return nil
+	}
+	checked := make(map[string]bool, len(transfers))
+	for _, t := range transfers {
+		addr := t.ToAddr.String()
+		if checked[addr] {
+			continue
+		}
+		checked[addr] = true
+		acc, err := c.queryClient.GetAccount(addr)
+		if err != nil {
+			return err
+		}
+		if types.IsMemoRequired(acc.Flags) {
+			return fmt.Errorf("memo is required for transfer to %s", addr)
+		}
+	}
+	return nil
+}
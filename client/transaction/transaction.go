@@ -1,7 +1,6 @@
 package transaction
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/binance-chain/go-sdk/client/basic"
@@ -34,6 +33,10 @@ type TransactionClient interface {
 	VoteProposal(proposalID int64, option msg.VoteOption, sync bool, options ...Option) (*VoteProposalResult, error)
 
 	GetKeyManager() keys.KeyManager
+
+	SendPayouts(report *PayoutReport, reportFile string, sync bool, options ...Option) error
+
+	DryRun(signed func(signedTx SignedTx)) TransactionClient
 }
 
 type client struct {
@@ -41,10 +44,29 @@ type client struct {
 	queryClient query.QueryClient
 	keyManager  keys.KeyManager
 	chainId     string
+
+	memoCheck bool
+	// dryRun receives the signed transactions instead of the API, see DryRun
+	dryRun func(signedTx SignedTx)
+}
+
+type ClientOption func(*client)
+
+// WithMemoCheck makes SendToken look up the flags of every recipient and refuse
+// to send without memo to the accounts that require one. It is disabled by default
+// since it costs one account query per recipient.
+func WithMemoCheck() ClientOption {
+	return func(c *client) {
+		c.memoCheck = true
+	}
 }
 
-func NewClient(chainId string, keyManager keys.KeyManager, queryClient query.QueryClient, basicClient basic.BasicClient) TransactionClient {
-	return &client{basicClient, queryClient, keyManager, chainId}
+func NewClient(chainId string, keyManager keys.KeyManager, queryClient query.QueryClient, basicClient basic.BasicClient, options ...ClientOption) TransactionClient {
+	c := &client{basicClient: basicClient, queryClient: queryClient, keyManager: keyManager, chainId: chainId}
+	for _, option := range options {
+		option(c)
+	}
+	return c
 }
 
 func (c *client) GetKeyManager() keys.KeyManager {
@@ -76,6 +98,24 @@ func WithAcNumAndSequence(accountNum, seq int64) Option {
 }
 
 func (c *client) broadcastMsg(m msg.Msg, sync bool, options ...Option) (*tx.TxCommitResult, error) {
+	signedTx, err := c.signMsg(m, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.broadcastSigned(signedTx, sync)
+}
+
+// broadcastSigned posts signedTx, or hands it to the dry run.
+func (c *client) broadcastSigned(signedTx *SignedTx, sync bool) (*tx.TxCommitResult, error) {
+	if c.dryRun != nil {
+		c.dryRun(*signedTx)
+		return &tx.TxCommitResult{Ok: true, Hash: signedTx.TxHash}, nil
+	}
+	return c.postTx([]byte(signedTx.HexTx), sync)
+}
+
+// signMsg builds, validates and signs the transaction of m.
+func (c *client) signMsg(m msg.Msg, options ...Option) (*SignedTx, error) {
 	// prepare message to sign
 	signMsg := &tx.StdSignMsg{
 		ChainID:       c.chainId,
@@ -90,6 +130,10 @@ func (c *client) broadcastMsg(m msg.Msg, sync bool, options ...Option) (*tx.TxCo
 		signMsg = op(signMsg)
 	}
 
+	if err := tx.ValidateMemo(signMsg.Memo); err != nil {
+		return nil, err
+	}
+
 	if signMsg.Sequence == -1 || signMsg.AccountNumber == -1 {
 		fromAddr := c.keyManager.GetAddr()
 		acc, err := c.queryClient.GetAccount(fromAddr.String())
@@ -112,21 +156,5 @@ func (c *client) broadcastMsg(m msg.Msg, sync bool, options ...Option) (*tx.TxCo
 		}
 	}
 
-	// Hex encoded signed transaction, ready to be posted to BncChain API
-	hexTx, err := c.keyManager.Sign(*signMsg)
-	if err != nil {
-		return nil, err
-	}
-	param := map[string]string{}
-	if sync {
-		param["sync"] = "true"
-	}
-	commits, err := c.basicClient.PostTx(hexTx, param)
-	if err != nil {
-		return nil, err
-	}
-	if len(commits) < 1 {
-		return nil, fmt.Errorf("Len of tx Commit result is less than 1 ")
-	}
-	return &commits[0], nil
+	return c.sign(*signMsg)
 }
@@ -0,0 +1,107 @@
+// Package cache keeps the answers of reference queries, such as the token and
+// trading pair lists, which change rarely.
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores values under string keys until their TTL expires or they are
+// invalidated. Implementations must be safe for concurrent use. The cached
+// values are shared by all the callers and must not be modified.
+type Cache interface {
+	Get(key string) (value interface{}, ok bool)
+	// Set stores value for ttl, a zero ttl never expires.
+	Set(key string, value interface{}, ttl time.Duration)
+	Delete(key string)
+	// DeletePrefix deletes every key starting with prefix.
+	DeletePrefix(prefix string)
+	Clear()
+}
+
+type entry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// MemoryCache is an in-memory Cache. Expired entries are dropped when they are read.
+type MemoryCache struct {
+	mtx     sync.RWMutex
+	entries map[string]entry
+	now     func() time.Time
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]entry), now: time.Now}
+}
+
+func (c *MemoryCache) Get(key string) (interface{}, bool) {
+	c.mtx.RLock()
+	e, ok := c.entries[key]
+	c.mtx.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.mtx.Lock()
+		if cur, ok := c.entries[key]; ok && cur.expires == e.expires {
+			delete(c.entries, key)
+		}
+		c.mtx.Unlock()
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *MemoryCache) Set(key string, value interface{}, ttl time.Duration) {
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expires = c.now().Add(ttl)
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries[key] = e
+}
+
+func (c *MemoryCache) Delete(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.entries, key)
+}
+
+func (c *MemoryCache) DeletePrefix(prefix string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *MemoryCache) Clear() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries = make(map[string]entry)
+}
+
+// GetOrLoad returns the value cached under key, or loads it and caches it for
+// ttl. Errors are not cached. A nil cache always loads.
+func GetOrLoad(c Cache, key string, ttl time.Duration, load func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return load()
+	}
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(key, value, ttl)
+	return value, nil
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCache() (*MemoryCache, *time.Time) {
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	c, now := newTestCache()
+	c.Set("short", 1, time.Second)
+	c.Set("long", 2, time.Minute)
+	c.Set("forever", 3, 0)
+
+	value, ok := c.Get("short")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	*now = now.Add(time.Second)
+	_, ok = c.Get("short")
+	assert.False(t, ok, "an entry expires at its TTL")
+	value, ok = c.Get("long")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	*now = now.Add(24 * time.Hour)
+	_, ok = c.Get("long")
+	assert.False(t, ok)
+	value, ok = c.Get("forever")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	// setting a key again restarts its TTL
+	c.Set("short", 4, time.Second)
+	value, ok = c.Get("short")
+	assert.True(t, ok)
+	assert.Equal(t, 4, value)
+}
+
+func TestMemoryCacheInvalidation(t *testing.T) {
+	c, _ := newTestCache()
+	for _, key := range []string{"rpc/tokens/BNB", "rpc/tokens/XYZ", "rpc/dex/pairs/0/10", "query/tokens/"} {
+		c.Set(key, key, 0)
+	}
+
+	c.Delete("rpc/tokens/XYZ")
+	_, ok := c.Get("rpc/tokens/XYZ")
+	assert.False(t, ok)
+
+	c.DeletePrefix("rpc/tokens/")
+	_, ok = c.Get("rpc/tokens/BNB")
+	assert.False(t, ok)
+	_, ok = c.Get("rpc/dex/pairs/0/10")
+	assert.True(t, ok)
+	_, ok = c.Get("query/tokens/")
+	assert.True(t, ok)
+
+	c.Clear()
+	_, ok = c.Get("rpc/dex/pairs/0/10")
+	assert.False(t, ok)
+	_, ok = c.Get("query/tokens/")
+	assert.False(t, ok)
+}
+
+func TestGetOrLoad(t *testing.T) {
+	c, now := newTestCache()
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := GetOrLoad(c, "key", time.Minute, load)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, value)
+	}
+	assert.Equal(t, 1, loads)
+
+	*now = now.Add(time.Minute)
+	value, err := GetOrLoad(c, "key", time.Minute, load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value, "an expired entry is loaded again")
+
+	c.Delete("key")
+	value, err = GetOrLoad(c, "key", time.Minute, load)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, value, "an invalidated entry is loaded again")
+
+	// errors are not cached
+	loadErr := errors.New("node is down")
+	_, err = GetOrLoad(c, "failing", time.Minute, func() (interface{}, error) {
+		return nil, loadErr
+	})
+	assert.Equal(t, loadErr, err)
+	value, err = GetOrLoad(c, "failing", time.Minute, load)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, value)
+
+	// without cache every call loads
+	for i := 5; i < 7; i++ {
+		value, err = GetOrLoad(nil, "key", time.Minute, load)
+		assert.NoError(t, err)
+		assert.Equal(t, i, value)
+	}
+}
+
+func TestMemoryCacheConcurrentUse(t *testing.T) {
+	c := NewMemoryCache()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Set("key", j, time.Millisecond)
+				c.Get("key")
+				if j%100 == 0 {
+					c.DeletePrefix("k")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,244 @@
+// Package paging walks the list endpoints page by page, so that callers get
+// every item without hand-rolling offset loops.
+package paging
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultPageSize   = 500
+	DefaultMaxRetries = 3
+
+	defaultRetryBackoff = 1 * time.Second
+)
+
+// ErrStop can be returned by an item callback to end the iteration early,
+// the iteration then returns nil.
+var ErrStop = errors.New("stop iteration")
+
+// FetchFunc fetches the page at offset. It returns the number of items of the
+// page and the total number of items, or -1 when the endpoint does not tell.
+type FetchFunc func(offset, limit int) (page interface{}, count int, total int, err error)
+
+// DeliverFunc hands the items of a page to the caller, in order.
+type DeliverFunc func(page interface{}) error
+
+// Pager fetches pages until the list is exhausted.
+type Pager struct {
+	pageSize     int
+	concurrency  int
+	interval     time.Duration
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+type Option func(*Pager)
+
+// WithPageSize sets the number of items requested per page.
+func WithPageSize(size int) Option {
+	return func(p *Pager) {
+		if size > 0 {
+			p.pageSize = size
+		}
+	}
+}
+
+// WithConcurrency fetches up to n pages at the same time once the total is
+// known. Items are still delivered in order.
+func WithConcurrency(n int) Option {
+	return func(p *Pager) {
+		if n > 0 {
+			p.concurrency = n
+		}
+	}
+}
+
+// WithInterval waits at least interval between two requests of the same
+// worker, to stay below the request limits of the API.
+func WithInterval(interval time.Duration) Option {
+	return func(p *Pager) {
+		if interval > 0 {
+			p.interval = interval
+		}
+	}
+}
+
+// WithMaxRetries sets how many times a rate limited page is retried, with an
+// exponential backoff.
+func WithMaxRetries(retries int) Option {
+	return func(p *Pager) {
+		if retries >= 0 {
+			p.maxRetries = retries
+		}
+	}
+}
+
+func NewPager(options ...Option) *Pager {
+	p := &Pager{
+		pageSize:     DefaultPageSize,
+		concurrency:  1,
+		maxRetries:   DefaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// PageSize returns the number of items requested per page.
+func (p *Pager) PageSize() int {
+	return p.pageSize
+}
+
+type pageResult struct {
+	page  interface{}
+	count int
+	err   error
+}
+
+// Run fetches the pages from offset 0 and delivers them, until a page is not
+// full, the total is reached, ctx is done or deliver returns an error.
+func (p *Pager) Run(ctx context.Context, fetch FetchFunc, deliver DeliverFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	page, count, total, err := p.fetch(ctx, fetch, 0)
+	if err != nil {
+		return err
+	}
+	if err := deliver(page); err != nil {
+		return stopped(err)
+	}
+	if count < p.pageSize || (total >= 0 && p.pageSize >= total) {
+		return nil
+	}
+	if total >= 0 && p.concurrency > 1 {
+		return p.fanOut(ctx, fetch, deliver, total)
+	}
+	for offset := p.pageSize; total < 0 || offset < total; offset += p.pageSize {
+		if err := p.wait(ctx); err != nil {
+			return err
+		}
+		page, count, _, err := p.fetch(ctx, fetch, offset)
+		if err != nil {
+			return err
+		}
+		if err := deliver(page); err != nil {
+			return stopped(err)
+		}
+		if count < p.pageSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// fanOut fetches the remaining pages concurrently and delivers them in order.
+func (p *Pager) fanOut(ctx context.Context, fetch FetchFunc, deliver DeliverFunc, total int) error {
+	pending := make(chan chan pageResult, p.concurrency)
+	sem := make(chan struct{}, p.concurrency)
+	go func() {
+		defer close(pending)
+		for offset := p.pageSize; offset < total; offset += p.pageSize {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			resCh := make(chan pageResult, 1)
+			select {
+			case pending <- resCh:
+			case <-ctx.Done():
+				<-sem
+				return
+			}
+			go func(offset int) {
+				defer func() { <-sem }()
+				if err := p.wait(ctx); err != nil {
+					resCh <- pageResult{err: err}
+					return
+				}
+				page, count, _, err := p.fetch(ctx, fetch, offset)
+				resCh <- pageResult{page, count, err}
+			}(offset)
+		}
+	}()
+
+	for resCh := range pending {
+		var res pageResult
+		select {
+		case res = <-resCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if res.err != nil {
+			return res.err
+		}
+		if err := deliver(res.page); err != nil {
+			return stopped(err)
+		}
+		if res.count < p.pageSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// fetch gets one page, retrying it while the API answers that the request
+// limit is exceeded.
+func (p *Pager) fetch(ctx context.Context, fetch FetchFunc, offset int) (interface{}, int, int, error) {
+	backoff := p.retryBackoff
+	for retry := 0; ; retry++ {
+		page, count, total, err := fetch(offset, p.pageSize)
+		if err == nil || retry >= p.maxRetries || !IsRateLimited(err) {
+			return page, count, total, err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, 0, 0, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+func (p *Pager) wait(ctx context.Context) error {
+	if p.interval <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-time.After(p.interval):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StatusCoder is implemented by the errors carrying the HTTP status code of
+// the response, such as the basic.StatusError of the API client.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// IsRateLimited tells whether err reports that the request limit of the API is
+// exceeded, with a 429 or a 418 status code.
+func IsRateLimited(err error) bool {
+	var coded StatusCoder
+	if !errors.As(err, &coded) {
+		return false
+	}
+	code := coded.StatusCode()
+	return code == http.StatusTooManyRequests || code == http.StatusTeapot
+}
+
+func stopped(err error) error {
+	if err == ErrStop {
+		return nil
+	}
+	return err
+}
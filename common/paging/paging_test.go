@@ -0,0 +1,168 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/binance-chain/go-sdk/client/basic"
+)
+
+// list serves the items 0..size-1 page by page.
+type list struct {
+	mtx         sync.Mutex
+	size        int
+	withTotal   bool
+	delay       func(offset int) time.Duration
+	failures    map[int][]error
+	calls       []int
+	inFlight    int
+	maxInFlight int
+}
+
+func (l *list) fetch(offset, limit int) (interface{}, int, int, error) {
+	l.mtx.Lock()
+	l.calls = append(l.calls, offset)
+	if errs := l.failures[offset]; len(errs) > 0 {
+		l.failures[offset] = errs[1:]
+		l.mtx.Unlock()
+		return nil, 0, 0, errs[0]
+	}
+	l.inFlight++
+	if l.inFlight > l.maxInFlight {
+		l.maxInFlight = l.inFlight
+	}
+	l.mtx.Unlock()
+	if l.delay != nil {
+		time.Sleep(l.delay(offset))
+	}
+	l.mtx.Lock()
+	l.inFlight--
+	l.mtx.Unlock()
+
+	page := make([]int, 0, limit)
+	for i := offset; i < offset+limit && i < l.size; i++ {
+		page = append(page, i)
+	}
+	total := -1
+	if l.withTotal {
+		total = l.size
+	}
+	return page, len(page), total, nil
+}
+
+func collect(items *[]int) DeliverFunc {
+	return func(page interface{}) error {
+		*items = append(*items, page.([]int)...)
+		return nil
+	}
+}
+
+func assertSequence(t *testing.T, items []int, n int) {
+	assert.Len(t, items, n)
+	for i, item := range items {
+		if !assert.Equal(t, i, item) {
+			return
+		}
+	}
+}
+
+func TestPagerStopsOnShortPage(t *testing.T) {
+	l := &list{size: 25}
+	var items []int
+	assert.NoError(t, NewPager(WithPageSize(10)).Run(context.Background(), l.fetch, collect(&items)))
+	assertSequence(t, items, 25)
+	assert.Equal(t, []int{0, 10, 20}, l.calls)
+
+	// a last full page needs one more, empty, page to be detected
+	l = &list{size: 20}
+	items = nil
+	assert.NoError(t, NewPager(WithPageSize(10)).Run(context.Background(), l.fetch, collect(&items)))
+	assertSequence(t, items, 20)
+	assert.Equal(t, []int{0, 10, 20}, l.calls)
+
+	// the total saves that request
+	l = &list{size: 20, withTotal: true}
+	items = nil
+	assert.NoError(t, NewPager(WithPageSize(10)).Run(context.Background(), l.fetch, collect(&items)))
+	assertSequence(t, items, 20)
+	assert.Equal(t, []int{0, 10}, l.calls)
+}
+
+func TestPagerConcurrentOrder(t *testing.T) {
+	// the later pages are served faster, so they complete out of order
+	l := &list{size: 205, withTotal: true, delay: func(offset int) time.Duration {
+		return time.Duration(210-offset) * 50 * time.Microsecond
+	}}
+	var items []int
+	err := NewPager(WithPageSize(10), WithConcurrency(4)).Run(context.Background(), l.fetch, collect(&items))
+	assert.NoError(t, err)
+	assertSequence(t, items, 205)
+	assert.Len(t, l.calls, 21)
+	assert.True(t, l.maxInFlight <= 4, "at most 4 pages fetched at once, got %d", l.maxInFlight)
+	assert.True(t, l.maxInFlight > 1, "pages should be fetched concurrently")
+}
+
+func TestPagerStop(t *testing.T) {
+	l := &list{size: 100, withTotal: true}
+	count := 0
+	err := NewPager(WithPageSize(10), WithConcurrency(3)).Run(context.Background(), l.fetch, func(page interface{}) error {
+		count += len(page.([]int))
+		if count >= 30 {
+			return ErrStop
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 30, count)
+
+	deliverErr := errors.New("disk full")
+	err = NewPager(WithPageSize(10)).Run(context.Background(), l.fetch, func(interface{}) error {
+		return deliverErr
+	})
+	assert.Equal(t, deliverErr, err)
+}
+
+func TestPagerRetriesRateLimited(t *testing.T) {
+	l := &list{size: 25, failures: map[int][]error{
+		10: {&basic.StatusError{Code: 429}, fmt.Errorf("page 10: %w", &basic.StatusError{Code: 418})},
+	}}
+	p := NewPager(WithPageSize(10))
+	p.retryBackoff = time.Millisecond
+	var items []int
+	assert.NoError(t, p.Run(context.Background(), l.fetch, collect(&items)))
+	assertSequence(t, items, 25)
+	assert.Equal(t, []int{0, 10, 10, 10, 20}, l.calls)
+
+	// too many retries
+	l = &list{size: 25, failures: map[int][]error{
+		0: {&basic.StatusError{Code: 429}, &basic.StatusError{Code: 429}},
+	}}
+	p = NewPager(WithPageSize(10), WithMaxRetries(1))
+	p.retryBackoff = time.Millisecond
+	err := p.Run(context.Background(), l.fetch, collect(new([]int)))
+	assert.True(t, IsRateLimited(err))
+	assert.Equal(t, []int{0, 0}, l.calls)
+
+	// other errors are not retried
+	l = &list{size: 25, failures: map[int][]error{
+		0: {&basic.StatusError{Code: 500}},
+	}}
+	err = NewPager(WithPageSize(10)).Run(context.Background(), l.fetch, collect(new([]int)))
+	assert.Error(t, err)
+	assert.Equal(t, []int{0}, l.calls)
+}
+
+func TestIsRateLimited(t *testing.T) {
+	assert.True(t, IsRateLimited(&basic.StatusError{Code: 429}))
+	assert.True(t, IsRateLimited(&basic.StatusError{Code: 418}))
+	assert.True(t, IsRateLimited(fmt.Errorf("query failed: %w", &basic.StatusError{Code: 429})))
+	assert.False(t, IsRateLimited(&basic.StatusError{Code: 404}))
+	assert.False(t, IsRateLimited(errors.New("bad response, status code 429")))
+	assert.False(t, IsRateLimited(nil))
+}
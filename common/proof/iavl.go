@@ -0,0 +1,297 @@
+package proof
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/tendermint/go-amino"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// Proof op types of the IAVL stores, as written by the node.
+const (
+	ProofOpIAVLValue   = "iavl:v"
+	ProofOpIAVLAbsence = "iavl:a"
+)
+
+// RangeProof is the IAVL proof of a range of leaves. Its layout follows the
+// one of the node, so that it decodes from the proof ops.
+type RangeProof struct {
+	LeftPath   PathToLeaf      `json:"left_path"`
+	InnerNodes []PathToLeaf    `json:"inner_nodes"`
+	Leaves     []ProofLeafNode `json:"leaves"`
+}
+
+// PathToLeaf lists the inner nodes from the root to a leaf.
+type PathToLeaf []ProofInnerNode
+
+type ProofInnerNode struct {
+	Height  int8   `json:"height"`
+	Size    int64  `json:"size"`
+	Version int64  `json:"version"`
+	Left    []byte `json:"left"`
+	Right   []byte `json:"right"`
+}
+
+type ProofLeafNode struct {
+	Key       cmn.HexBytes `json:"key"`
+	ValueHash cmn.HexBytes `json:"value"`
+	Version   int64        `json:"version"`
+}
+
+// Hash returns the hash of the node whose other child hashes to childHash.
+// Only one of Left and Right is set, the other side is the child.
+func (pin ProofInnerNode) Hash(childHash []byte) ([]byte, error) {
+	if len(pin.Left) > 0 && len(pin.Right) > 0 {
+		return nil, fmt.Errorf("invalid iavl proof: inner node has both left and right hashes")
+	}
+	buf := new(bytes.Buffer)
+	amino.EncodeInt8(buf, pin.Height)
+	amino.EncodeVarint(buf, pin.Size)
+	amino.EncodeVarint(buf, pin.Version)
+	if len(pin.Left) == 0 {
+		amino.EncodeByteSlice(buf, childHash)
+		amino.EncodeByteSlice(buf, pin.Right)
+	} else {
+		amino.EncodeByteSlice(buf, pin.Left)
+		amino.EncodeByteSlice(buf, childHash)
+	}
+	return tmhash.Sum(buf.Bytes()), nil
+}
+
+func (pln ProofLeafNode) Hash() []byte {
+	buf := new(bytes.Buffer)
+	amino.EncodeInt8(buf, 0)
+	amino.EncodeVarint(buf, 1)
+	amino.EncodeVarint(buf, pln.Version)
+	amino.EncodeByteSlice(buf, pln.Key)
+	amino.EncodeByteSlice(buf, pln.ValueHash)
+	return tmhash.Sum(buf.Bytes())
+}
+
+func (pl PathToLeaf) rootHash(leaf ProofLeafNode) ([]byte, error) {
+	hash := leaf.Hash()
+	for i := len(pl) - 1; i >= 0; i-- {
+		var err error
+		if hash, err = pl[i].Hash(hash); err != nil {
+			return nil, err
+		}
+	}
+	return hash, nil
+}
+
+func (pl PathToLeaf) isLeftmost() bool {
+	for _, node := range pl {
+		if len(node.Left) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (pl PathToLeaf) isRightmost() bool {
+	for _, node := range pl {
+		if len(node.Right) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ComputeRootHash returns the root hash proven by the proof, and whether its
+// last leaf is the last one of the tree.
+func (proof *RangeProof) ComputeRootHash() (root []byte, treeEnd bool, err error) {
+	if len(proof.Leaves) == 0 {
+		return nil, false, fmt.Errorf("invalid iavl proof: no leaves")
+	}
+	if len(proof.InnerNodes)+1 != len(proof.Leaves) {
+		return nil, false, fmt.Errorf("invalid iavl proof: %d inner paths for %d leaves", len(proof.InnerNodes), len(proof.Leaves))
+	}
+	leaves := proof.Leaves
+	inners := proof.InnerNodes
+
+	// computeHash proves the leaves along path, the hashes of the right
+	// siblings are checked against the paths of the next leaves.
+	var computeHash func(path PathToLeaf, rightmost bool) (hash []byte, treeEnd bool, done bool, err error)
+	computeHash = func(path PathToLeaf, rightmost bool) ([]byte, bool, bool, error) {
+		leaf := leaves[0]
+		leaves = leaves[1:]
+		hash, err := path.rootHash(leaf)
+		if err != nil {
+			return nil, false, false, err
+		}
+		if len(leaves) == 0 {
+			return hash, rightmost && path.isRightmost(), true, nil
+		}
+		for len(path) > 0 {
+			last := path[len(path)-1]
+			path = path[:len(path)-1]
+			if len(last.Right) == 0 {
+				continue
+			}
+			if len(inners) == 0 {
+				return nil, false, false, fmt.Errorf("invalid iavl proof: missing inner path")
+			}
+			next := inners[0]
+			inners = inners[1:]
+			derived, treeEnd, done, err := computeHash(next, rightmost && path.isRightmost())
+			if err != nil {
+				return nil, treeEnd, false, err
+			}
+			if !bytes.Equal(derived, last.Right) {
+				return nil, treeEnd, false, fmt.Errorf("invalid iavl proof: intermediate hash %X does not match %X", derived, last.Right)
+			}
+			if done {
+				return hash, treeEnd, true, nil
+			}
+		}
+		return hash, false, false, nil
+	}
+
+	root, treeEnd, done, err := computeHash(proof.LeftPath, true)
+	if err != nil {
+		return nil, treeEnd, err
+	}
+	if !done {
+		return nil, treeEnd, fmt.Errorf("invalid iavl proof: leaves left over")
+	}
+	return root, treeEnd, nil
+}
+
+// VerifyItem checks that the proof holds key with value.
+func (proof *RangeProof) VerifyItem(key, value []byte) error {
+	leaves := proof.Leaves
+	i := sort.Search(len(leaves), func(i int) bool {
+		return bytes.Compare(key, leaves[i].Key) <= 0
+	})
+	if i >= len(leaves) || !bytes.Equal(leaves[i].Key, key) {
+		return fmt.Errorf("invalid iavl proof: key %X is not in the proof", key)
+	}
+	if !bytes.Equal(leaves[i].ValueHash, tmhash.Sum(value)) {
+		return fmt.Errorf("invalid iavl proof: value of key %X does not match", key)
+	}
+	return nil
+}
+
+// VerifyAbsence checks that the proof shows key is not in the tree. treeEnd
+// is returned by ComputeRootHash.
+func (proof *RangeProof) VerifyAbsence(key []byte, treeEnd bool) error {
+	if len(proof.Leaves) == 0 {
+		return fmt.Errorf("invalid iavl proof: no leaves")
+	}
+	cmp := bytes.Compare(key, proof.Leaves[0].Key)
+	if cmp < 0 {
+		if proof.LeftPath.isLeftmost() {
+			return nil
+		}
+		return fmt.Errorf("invalid iavl proof: absence of %X not proved by the left path", key)
+	} else if cmp == 0 {
+		return fmt.Errorf("invalid iavl proof: key %X exists", key)
+	}
+	if len(proof.LeftPath) == 0 || proof.LeftPath.isRightmost() {
+		return nil
+	}
+	for _, leaf := range proof.Leaves[1:] {
+		cmp := bytes.Compare(key, leaf.Key)
+		if cmp < 0 {
+			return nil
+		} else if cmp == 0 {
+			return fmt.Errorf("invalid iavl proof: key %X exists", key)
+		}
+	}
+	if treeEnd {
+		return nil
+	}
+	return fmt.Errorf("invalid iavl proof: absence of %X not proved by the right leaf", key)
+}
+
+// IAVLValueOp proves that a key of an IAVL store holds a value.
+type IAVLValueOp struct {
+	key   []byte
+	Proof *RangeProof `json:"proof"`
+}
+
+var _ merkle.ProofOperator = IAVLValueOp{}
+
+func IAVLValueOpDecoder(pop merkle.ProofOp) (merkle.ProofOperator, error) {
+	if pop.Type != ProofOpIAVLValue {
+		return nil, fmt.Errorf("unexpected proof op type %s, want %s", pop.Type, ProofOpIAVLValue)
+	}
+	var op IAVLValueOp
+	if err := cdc.UnmarshalBinaryLengthPrefixed(pop.Data, &op); err != nil {
+		return nil, fmt.Errorf("failed to decode iavl value proof: %s", err.Error())
+	}
+	if op.Proof == nil {
+		return nil, fmt.Errorf("invalid iavl proof: empty proof")
+	}
+	return IAVLValueOp{key: pop.Key, Proof: op.Proof}, nil
+}
+
+func (op IAVLValueOp) Run(args [][]byte) ([][]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("iavl value proof expects 1 value, got %d", len(args))
+	}
+	root, _, err := op.Proof.ComputeRootHash()
+	if err != nil {
+		return nil, err
+	}
+	if err := op.Proof.VerifyItem(op.key, args[0]); err != nil {
+		return nil, err
+	}
+	return [][]byte{root}, nil
+}
+
+func (op IAVLValueOp) GetKey() []byte {
+	return op.key
+}
+
+func (op IAVLValueOp) ProofOp() merkle.ProofOp {
+	return merkle.ProofOp{Type: ProofOpIAVLValue, Key: op.key, Data: cdc.MustMarshalBinaryLengthPrefixed(op)}
+}
+
+// IAVLAbsenceOp proves that a key is not in an IAVL store.
+type IAVLAbsenceOp struct {
+	key   []byte
+	Proof *RangeProof `json:"proof"`
+}
+
+var _ merkle.ProofOperator = IAVLAbsenceOp{}
+
+func IAVLAbsenceOpDecoder(pop merkle.ProofOp) (merkle.ProofOperator, error) {
+	if pop.Type != ProofOpIAVLAbsence {
+		return nil, fmt.Errorf("unexpected proof op type %s, want %s", pop.Type, ProofOpIAVLAbsence)
+	}
+	var op IAVLAbsenceOp
+	if err := cdc.UnmarshalBinaryLengthPrefixed(pop.Data, &op); err != nil {
+		return nil, fmt.Errorf("failed to decode iavl absence proof: %s", err.Error())
+	}
+	if op.Proof == nil {
+		return nil, fmt.Errorf("invalid iavl proof: empty proof")
+	}
+	return IAVLAbsenceOp{key: pop.Key, Proof: op.Proof}, nil
+}
+
+func (op IAVLAbsenceOp) Run(args [][]byte) ([][]byte, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("iavl absence proof expects no value, got %d", len(args))
+	}
+	root, treeEnd, err := op.Proof.ComputeRootHash()
+	if err != nil {
+		return nil, err
+	}
+	if err := op.Proof.VerifyAbsence(op.key, treeEnd); err != nil {
+		return nil, err
+	}
+	return [][]byte{root}, nil
+}
+
+func (op IAVLAbsenceOp) GetKey() []byte {
+	return op.key
+}
+
+func (op IAVLAbsenceOp) ProofOp() merkle.ProofOp {
+	return merkle.ProofOp{Type: ProofOpIAVLAbsence, Key: op.key, Data: cdc.MustMarshalBinaryLengthPrefixed(op)}
+}
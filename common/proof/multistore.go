@@ -0,0 +1,114 @@
+package proof
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// ProofOpMultiStore is the proof op type linking a store root to the app hash.
+const ProofOpMultiStore = "multistore"
+
+// CommitID is the version and root hash of a store.
+type CommitID struct {
+	Version int64  `json:"version"`
+	Hash    []byte `json:"hash"`
+}
+
+type StoreCore struct {
+	CommitID CommitID `json:"commit_id"`
+}
+
+type StoreInfo struct {
+	Name string    `json:"name"`
+	Core StoreCore `json:"core"`
+}
+
+// Hash leaves the name out, the multistore hash includes it as the map key.
+func (si StoreInfo) Hash() []byte {
+	return tmhash.Sum(cdc.MustMarshalBinaryLengthPrefixed(si.Core))
+}
+
+// MultiStoreProof lists the roots of all the stores of the app.
+type MultiStoreProof struct {
+	StoreInfos []StoreInfo `json:"store_infos"`
+}
+
+// validate checks that the stores are listed once each, sorted by name like
+// the multistore lists them: a second entry of a store could claim another
+// root for it than the one hashed into the app hash.
+func (proof *MultiStoreProof) validate() error {
+	for i := 1; i < len(proof.StoreInfos); i++ {
+		prev, name := proof.StoreInfos[i-1].Name, proof.StoreInfos[i].Name
+		if prev == name {
+			return fmt.Errorf("invalid multistore proof: store %s is listed twice", name)
+		}
+		if prev > name {
+			return fmt.Errorf("invalid multistore proof: store %s is listed after %s", name, prev)
+		}
+	}
+	return nil
+}
+
+// ComputeRootHash returns the app hash of the stores.
+func (proof *MultiStoreProof) ComputeRootHash() []byte {
+	m := make(map[string][]byte, len(proof.StoreInfos))
+	for _, si := range proof.StoreInfos {
+		m[si.Name] = si.Hash()
+	}
+	return merkle.SimpleHashFromMap(m)
+}
+
+// MultiStoreProofOp proves that the root of the store named key is part of the app hash.
+type MultiStoreProofOp struct {
+	key   []byte
+	Proof *MultiStoreProof `json:"proof"`
+}
+
+var _ merkle.ProofOperator = MultiStoreProofOp{}
+
+func MultiStoreProofOpDecoder(pop merkle.ProofOp) (merkle.ProofOperator, error) {
+	if pop.Type != ProofOpMultiStore {
+		return nil, fmt.Errorf("unexpected proof op type %s, want %s", pop.Type, ProofOpMultiStore)
+	}
+	var op MultiStoreProofOp
+	if err := cdc.UnmarshalBinaryLengthPrefixed(pop.Data, &op); err != nil {
+		return nil, fmt.Errorf("failed to decode multistore proof: %s", err.Error())
+	}
+	if op.Proof == nil {
+		return nil, fmt.Errorf("invalid multistore proof: empty proof")
+	}
+	if err := op.Proof.validate(); err != nil {
+		return nil, err
+	}
+	return MultiStoreProofOp{key: pop.Key, Proof: op.Proof}, nil
+}
+
+func (op MultiStoreProofOp) Run(args [][]byte) ([][]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("multistore proof expects 1 root, got %d", len(args))
+	}
+	root := args[0]
+	if err := op.Proof.validate(); err != nil {
+		return nil, err
+	}
+	for _, si := range op.Proof.StoreInfos {
+		if si.Name == string(op.key) {
+			if !bytes.Equal(si.Core.CommitID.Hash, root) {
+				return nil, fmt.Errorf("invalid multistore proof: root of store %s is %X, got %X", si.Name, si.Core.CommitID.Hash, root)
+			}
+			return [][]byte{op.Proof.ComputeRootHash()}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid multistore proof: store %s not found", string(op.key))
+}
+
+func (op MultiStoreProofOp) GetKey() []byte {
+	return op.key
+}
+
+func (op MultiStoreProofOp) ProofOp() merkle.ProofOp {
+	return merkle.ProofOp{Type: ProofOpMultiStore, Key: op.key, Data: cdc.MustMarshalBinaryLengthPrefixed(op)}
+}
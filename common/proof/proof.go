@@ -0,0 +1,43 @@
+// Package proof verifies the Merkle proofs of the ABCI store queries against
+// the app hash of a block header.
+package proof
+
+import (
+	"fmt"
+
+	"github.com/tendermint/go-amino"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+var cdc = amino.NewCodec()
+
+// DefaultProofRuntime decodes the proof ops of the node: IAVL values and
+// absences chained to the multistore root.
+func DefaultProofRuntime() *merkle.ProofRuntime {
+	prt := merkle.DefaultProofRuntime()
+	prt.RegisterOpDecoder(ProofOpIAVLValue, IAVLValueOpDecoder)
+	prt.RegisterOpDecoder(ProofOpIAVLAbsence, IAVLAbsenceOpDecoder)
+	prt.RegisterOpDecoder(ProofOpMultiStore, MultiStoreProofOpDecoder)
+	return prt
+}
+
+// StoreKeyPath is the key path of key in the store storeName.
+func StoreKeyPath(storeName string, key []byte) string {
+	return merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL).
+		String()
+}
+
+// VerifyStoreValue checks that key of storeName holds value under appHash, a
+// nil value checks that the key is absent.
+func VerifyStoreValue(prt *merkle.ProofRuntime, p *merkle.Proof, appHash []byte, storeName string, key, value []byte) error {
+	if p == nil || len(p.Ops) == 0 {
+		return fmt.Errorf("the node returned no proof")
+	}
+	keyPath := StoreKeyPath(storeName, key)
+	if value == nil {
+		return prt.VerifyAbsence(p, appHash, keyPath)
+	}
+	return prt.VerifyValue(p, appHash, keyPath, value)
+}
@@ -0,0 +1,226 @@
+package proof
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// fixtures are the proofs of an acc store of four leaves, account:b,
+// account:d, account:f and account:h, next to a main and a tokens store.
+type fixtures struct {
+	AppHash string `json:"app_hash"`
+	Store   string `json:"store"`
+	Cases   map[string]struct {
+		Key   string        `json:"key"`
+		Value string        `json:"value"`
+		Proof *merkle.Proof `json:"proof"`
+	} `json:"cases"`
+}
+
+func loadFixtures(t *testing.T) (*fixtures, []byte) {
+	bz, err := ioutil.ReadFile("testdata/proofs.json")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	f := new(fixtures)
+	assert.NoError(t, json.Unmarshal(bz, f))
+	appHash, err := hex.DecodeString(f.AppHash)
+	assert.NoError(t, err)
+	return f, appHash
+}
+
+// rangeProof returns the decoded IAVL proof of the fixture name, and a
+// function encoding it back into a copy of the fixture's proof.
+func rangeProof(t *testing.T, f *fixtures, name string) (*RangeProof, func() *merkle.Proof) {
+	fixture := f.Cases[name]
+	pop := fixture.Proof.Ops[0]
+	op, err := DefaultProofRuntime().Decode(pop)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var rp *RangeProof
+	switch op := op.(type) {
+	case IAVLValueOp:
+		rp = op.Proof
+	case IAVLAbsenceOp:
+		rp = op.Proof
+	}
+	encode := func() *merkle.Proof {
+		var iavlOp merkle.ProofOp
+		if pop.Type == ProofOpIAVLValue {
+			iavlOp = IAVLValueOp{key: pop.Key, Proof: rp}.ProofOp()
+		} else {
+			iavlOp = IAVLAbsenceOp{key: pop.Key, Proof: rp}.ProofOp()
+		}
+		return &merkle.Proof{Ops: []merkle.ProofOp{iavlOp, fixture.Proof.Ops[1]}}
+	}
+	return rp, encode
+}
+
+func TestVerifyStoreValue(t *testing.T) {
+	f, appHash := loadFixtures(t)
+	prt := DefaultProofRuntime()
+	for _, name := range []string{"value_b", "value_d", "value_f", "value_h"} {
+		fixture := f.Cases[name]
+		assert.NoError(t, VerifyStoreValue(prt, fixture.Proof, appHash, f.Store, []byte(fixture.Key), []byte(fixture.Value)), name)
+		assert.Error(t, VerifyStoreValue(prt, fixture.Proof, appHash, f.Store, []byte(fixture.Key), []byte("mallory")), name)
+		assert.Error(t, VerifyStoreValue(prt, fixture.Proof, appHash, f.Store, []byte(fixture.Key), nil), name)
+	}
+
+	// the proof of a key does not prove another one
+	fixture := f.Cases["value_b"]
+	assert.Error(t, VerifyStoreValue(prt, fixture.Proof, appHash, f.Store, []byte("account:d"), []byte("bob")))
+	assert.Error(t, VerifyStoreValue(prt, nil, appHash, f.Store, []byte(fixture.Key), []byte(fixture.Value)))
+	assert.Error(t, VerifyStoreValue(prt, fixture.Proof, tmhash.Sum([]byte("other")), f.Store, []byte(fixture.Key), []byte(fixture.Value)))
+}
+
+func TestVerifyStoreValueTamperedLeaf(t *testing.T) {
+	f, appHash := loadFixtures(t)
+	rp, encode := rangeProof(t, f, "value_d")
+	rp.Leaves[0].ValueHash = tmhash.Sum([]byte("mallory"))
+
+	err := VerifyStoreValue(DefaultProofRuntime(), encode(), appHash, f.Store, []byte("account:d"), []byte("mallory"))
+	assert.Error(t, err)
+}
+
+func TestVerifyStoreValueTamperedInnerNode(t *testing.T) {
+	f, appHash := loadFixtures(t)
+	prt := DefaultProofRuntime()
+
+	rp, encode := rangeProof(t, f, "value_f")
+	rp.LeftPath[1].Right[0] ^= 1
+	assert.Error(t, VerifyStoreValue(prt, encode(), appHash, f.Store, []byte("account:f"), []byte("carol")))
+
+	rp, encode = rangeProof(t, f, "value_f")
+	rp.LeftPath[0].Size++
+	assert.Error(t, VerifyStoreValue(prt, encode(), appHash, f.Store, []byte("account:f"), []byte("carol")))
+
+	// the inner path of the second leaf must hash to the sibling of the first
+	rp, encode = rangeProof(t, f, "absence_middle")
+	rp.InnerNodes[0][0].Right[0] ^= 1
+	_, _, err := rp.ComputeRootHash()
+	assert.Error(t, err)
+	assert.Error(t, VerifyStoreValue(prt, encode(), appHash, f.Store, []byte("account:e"), nil))
+}
+
+func TestVerifyStoreAbsence(t *testing.T) {
+	f, appHash := loadFixtures(t)
+	prt := DefaultProofRuntime()
+	for _, name := range []string{"absence_left", "absence_middle", "absence_right"} {
+		fixture := f.Cases[name]
+		assert.NoError(t, VerifyStoreValue(prt, fixture.Proof, appHash, f.Store, []byte(fixture.Key), nil), name)
+		assert.Error(t, VerifyStoreValue(prt, fixture.Proof, appHash, f.Store, []byte(fixture.Key), []byte("alice")), name)
+	}
+}
+
+func TestRangeProofEdges(t *testing.T) {
+	f, _ := loadFixtures(t)
+
+	left, _ := rangeProof(t, f, "absence_left")
+	_, treeEnd, err := left.ComputeRootHash()
+	assert.NoError(t, err)
+	assert.False(t, treeEnd)
+	assert.NoError(t, left.VerifyAbsence([]byte("account:a"), treeEnd))
+	assert.NoError(t, left.VerifyAbsence([]byte(""), treeEnd))
+	assert.Error(t, left.VerifyAbsence([]byte("account:b"), treeEnd), "the key exists")
+	assert.Error(t, left.VerifyAbsence([]byte("account:c"), treeEnd), "the next leaf is not in the proof")
+
+	right, _ := rangeProof(t, f, "absence_right")
+	_, treeEnd, err = right.ComputeRootHash()
+	assert.NoError(t, err)
+	assert.True(t, treeEnd)
+	assert.NoError(t, right.VerifyAbsence([]byte("account:z"), treeEnd))
+	assert.Error(t, right.VerifyAbsence([]byte("account:h"), treeEnd), "the key exists")
+	assert.Error(t, right.VerifyAbsence([]byte("account:g"), treeEnd), "the previous leaf is not in the proof")
+
+	// a leaf in the middle of the tree proves neither edge
+	middle, _ := rangeProof(t, f, "value_d")
+	_, treeEnd, err = middle.ComputeRootHash()
+	assert.NoError(t, err)
+	assert.False(t, treeEnd)
+	assert.Error(t, middle.VerifyAbsence([]byte("account:a"), treeEnd))
+	assert.Error(t, middle.VerifyAbsence([]byte("account:z"), treeEnd))
+
+	// the proofs of the leaves all hash to the same root
+	root, _, err := left.ComputeRootHash()
+	assert.NoError(t, err)
+	for _, name := range []string{"value_b", "value_d", "value_f", "value_h", "absence_middle", "absence_right"} {
+		rp, _ := rangeProof(t, f, name)
+		other, _, err := rp.ComputeRootHash()
+		assert.NoError(t, err, name)
+		assert.Equal(t, root, other, name)
+	}
+}
+
+func TestVerifyStoreValueWrongStore(t *testing.T) {
+	f, appHash := loadFixtures(t)
+	prt := DefaultProofRuntime()
+	fixture := f.Cases["value_b"]
+
+	// the key path names another store than the proof
+	assert.Error(t, VerifyStoreValue(prt, fixture.Proof, appHash, "tokens", []byte(fixture.Key), []byte(fixture.Value)))
+
+	// the proof claims the acc root is the one of another store
+	op, err := prt.Decode(fixture.Proof.Ops[1])
+	assert.NoError(t, err)
+	msOp := op.(MultiStoreProofOp)
+	for _, name := range []string{"tokens", "gov"} {
+		msOp.key = []byte(name)
+		p := &merkle.Proof{Ops: []merkle.ProofOp{fixture.Proof.Ops[0], msOp.ProofOp()}}
+		assert.Error(t, VerifyStoreValue(prt, p, appHash, name, []byte(fixture.Key), []byte(fixture.Value)), name)
+	}
+}
+
+func TestVerifyStoreValueForgedStoreInfo(t *testing.T) {
+	f, appHash := loadFixtures(t)
+	prt := DefaultProofRuntime()
+	fixture := f.Cases["value_b"]
+	op, err := prt.Decode(fixture.Proof.Ops[1])
+	assert.NoError(t, err)
+	msOp := op.(MultiStoreProofOp)
+	infos := msOp.Proof.StoreInfos
+
+	// a one leaf tree of the forged value, whose root is claimed for acc in
+	// an entry shadowed by the real one when hashing the app hash
+	forged := &RangeProof{Leaves: []ProofLeafNode{{Key: []byte(fixture.Key), ValueHash: tmhash.Sum([]byte("mallory")), Version: 1}}}
+	forgedRoot, _, err := forged.ComputeRootHash()
+	assert.NoError(t, err)
+	iavlOp := IAVLValueOp{key: []byte(fixture.Key), Proof: forged}.ProofOp()
+	forgedInfo := StoreInfo{Name: f.Store, Core: StoreCore{CommitID: CommitID{Version: 1, Hash: forgedRoot}}}
+	shadowed := &MultiStoreProof{StoreInfos: append([]StoreInfo{forgedInfo}, infos...)}
+	assert.Equal(t, appHash, shadowed.ComputeRootHash(), "the forged entry does not change the app hash")
+	p := &merkle.Proof{Ops: []merkle.ProofOp{iavlOp, MultiStoreProofOp{key: msOp.key, Proof: shadowed}.ProofOp()}}
+	err = VerifyStoreValue(prt, p, appHash, f.Store, []byte(fixture.Key), []byte("mallory"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "listed twice")
+	}
+
+	// the stores must be sorted, although their order does not change the hash
+	if assert.True(t, len(infos) > 1) {
+		unsorted := append([]StoreInfo{infos[len(infos)-1]}, infos[:len(infos)-1]...)
+		p = &merkle.Proof{Ops: []merkle.ProofOp{fixture.Proof.Ops[0], MultiStoreProofOp{key: msOp.key, Proof: &MultiStoreProof{StoreInfos: unsorted}}.ProofOp()}}
+		assert.Error(t, VerifyStoreValue(prt, p, appHash, f.Store, []byte(fixture.Key), []byte(fixture.Value)))
+	}
+}
+
+func TestProofInnerNodeBothChildren(t *testing.T) {
+	f, appHash := loadFixtures(t)
+	rp, encode := rangeProof(t, f, "value_f")
+	node := &rp.LeftPath[0]
+	if len(node.Left) == 0 {
+		node.Left = node.Right
+	} else {
+		node.Right = node.Left
+	}
+	_, err := node.Hash(tmhash.Sum([]byte("child")))
+	assert.Error(t, err)
+	_, _, err = rp.ComputeRootHash()
+	assert.Error(t, err)
+	assert.Error(t, VerifyStoreValue(DefaultProofRuntime(), encode(), appHash, f.Store, []byte("account:f"), []byte("carol")))
+}
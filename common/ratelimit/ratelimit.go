@@ -0,0 +1,233 @@
+// Package ratelimit keeps the clients below the request limits of the API with
+// one token bucket per group of endpoints.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Group is a set of endpoints sharing a request limit.
+type Group string
+
+const (
+	GroupQuery     Group = "query"
+	GroupBroadcast Group = "broadcast"
+	GroupWSConnect Group = "ws_connect"
+)
+
+// DefaultRetryAfter is used when a rate limited response has no valid Retry-After header.
+const DefaultRetryAfter = 1 * time.Second
+
+// Limit allows Rate requests per second on average, and bursts of Burst
+// requests. A zero Rate does not limit the group.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultLimits stay below the per IP limits of the public API.
+var DefaultLimits = map[Group]Limit{
+	GroupQuery:     {Rate: 10, Burst: 10},
+	GroupBroadcast: {Rate: 5, Burst: 5},
+	GroupWSConnect: {Rate: 1, Burst: 5},
+}
+
+// Stats are the wait time metrics of a group.
+type Stats struct {
+	Requests  int64         `json:"requests"`
+	Delayed   int64         `json:"delayed"`
+	TotalWait time.Duration `json:"total_wait"`
+	MaxWait   time.Duration `json:"max_wait"`
+	// BlockedUntil is the end of the last Retry-After period of the group.
+	BlockedUntil time.Time `json:"blocked_until"`
+}
+
+// WaitObserver receives the time every request waited, to export it as a metric.
+type WaitObserver func(group Group, wait time.Duration)
+
+type bucket struct {
+	limit        Limit
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	stats        Stats
+}
+
+// RateLimiter is safe for concurrent use and is meant to be shared by all the
+// clients talking to the same API.
+type RateLimiter struct {
+	mtx      sync.Mutex
+	buckets  map[Group]*bucket
+	observer WaitObserver
+	now      func() time.Time
+}
+
+type Option func(*RateLimiter)
+
+// WithLimit sets the limit of group.
+func WithLimit(group Group, limit Limit) Option {
+	return func(l *RateLimiter) {
+		l.buckets[group] = &bucket{limit: limit, tokens: float64(limit.Burst)}
+	}
+}
+
+// WithWaitObserver sets the function receiving the wait time of every request.
+func WithWaitObserver(observer WaitObserver) Option {
+	return func(l *RateLimiter) {
+		l.observer = observer
+	}
+}
+
+// WithClock sets the function telling the current time, time.Now by default.
+// The buckets are refilled and the Retry-After periods measured with it.
+func WithClock(now func() time.Time) Option {
+	return func(l *RateLimiter) {
+		if now != nil {
+			l.now = now
+		}
+	}
+}
+
+// NewRateLimiter returns a limiter using DefaultLimits, overridden by options.
+func NewRateLimiter(options ...Option) *RateLimiter {
+	l := &RateLimiter{buckets: make(map[Group]*bucket), now: time.Now}
+	for group, limit := range DefaultLimits {
+		WithLimit(group, limit)(l)
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// Wait blocks until a request of group is allowed, or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, group Group) error {
+	wait := l.reserve(group)
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.cancel(group)
+			return ctx.Err()
+		}
+	}
+	if l.observer != nil {
+		l.observer(group, wait)
+	}
+	return nil
+}
+
+// Block makes the requests of group wait for d, as asked by a Retry-After header.
+func (l *RateLimiter) Block(group Group, d time.Duration) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	b := l.bucket(group)
+	if until := l.now().Add(d); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+		b.stats.BlockedUntil = until
+	}
+}
+
+// ObserveResponse blocks group for the Retry-After period of a rate limited
+// response, and reports whether the response was rate limited.
+func (l *RateLimiter) ObserveResponse(group Group, statusCode int, header http.Header) bool {
+	if statusCode != http.StatusTooManyRequests && statusCode != http.StatusTeapot {
+		return false
+	}
+	l.Block(group, RetryAfter(header, l.now()))
+	return true
+}
+
+// Stats returns the metrics of group.
+func (l *RateLimiter) Stats(group Group) Stats {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.bucket(group).stats
+}
+
+// AllStats returns the metrics of every group.
+func (l *RateLimiter) AllStats() map[Group]Stats {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	stats := make(map[Group]Stats, len(l.buckets))
+	for group, b := range l.buckets {
+		stats[group] = b.stats
+	}
+	return stats
+}
+
+// reserve takes a token of group and returns how long the request has to wait for it.
+func (l *RateLimiter) reserve(group Group) time.Duration {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	b := l.bucket(group)
+	now := l.now()
+	var wait time.Duration
+	if b.limit.Rate > 0 {
+		if !b.last.IsZero() {
+			b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+		}
+		if max := float64(b.limit.Burst); b.tokens > max {
+			b.tokens = max
+		}
+		b.last = now
+		b.tokens--
+		if b.tokens < 0 {
+			wait = time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+		}
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	b.stats.Requests++
+	if wait > 0 {
+		b.stats.Delayed++
+		b.stats.TotalWait += wait
+		if wait > b.stats.MaxWait {
+			b.stats.MaxWait = wait
+		}
+	}
+	return wait
+}
+
+// cancel gives back the token of a request which did not wait until its turn.
+func (l *RateLimiter) cancel(group Group) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if b := l.bucket(group); b.limit.Rate > 0 {
+		b.tokens++
+	}
+}
+
+func (l *RateLimiter) bucket(group Group) *bucket {
+	b, ok := l.buckets[group]
+	if !ok {
+		b = &bucket{}
+		l.buckets[group] = b
+	}
+	return b
+}
+
+// RetryAfter parses the Retry-After header, given in seconds or as an HTTP date.
+func RetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return DefaultRetryAfter
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return DefaultRetryAfter
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	mtx sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	c.now = c.now.Add(d)
+	c.mtx.Unlock()
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestTokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	var waits []time.Duration
+	l := NewRateLimiter(
+		WithClock(clock.Now),
+		WithLimit(GroupQuery, Limit{Rate: 1000, Burst: 2}),
+		WithWaitObserver(func(group Group, wait time.Duration) {
+			assert.Equal(t, GroupQuery, group)
+			waits = append(waits, wait)
+		}))
+
+	// the burst is served at once, then one request per millisecond
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, l.Wait(context.Background(), GroupQuery))
+	}
+	assert.Equal(t, []time.Duration{0, 0, time.Millisecond, 2 * time.Millisecond}, waits)
+	stats := l.Stats(GroupQuery)
+	assert.Equal(t, int64(4), stats.Requests)
+	assert.Equal(t, int64(2), stats.Delayed)
+	assert.Equal(t, 3*time.Millisecond, stats.TotalWait)
+	assert.Equal(t, 2*time.Millisecond, stats.MaxWait)
+
+	// the bucket refills with time, up to the burst
+	clock.Advance(time.Second)
+	waits = nil
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, l.Wait(context.Background(), GroupQuery))
+	}
+	assert.Equal(t, []time.Duration{0, 0, time.Millisecond}, waits)
+}
+
+func TestWaitCanceledGivesTokenBack(t *testing.T) {
+	clock := newFakeClock()
+	l := NewRateLimiter(WithClock(clock.Now), WithLimit(GroupBroadcast, Limit{Rate: 1, Burst: 1}))
+
+	assert.NoError(t, l.Wait(context.Background(), GroupBroadcast))
+	assert.Equal(t, context.Canceled, l.Wait(canceledContext(), GroupBroadcast))
+	assert.Equal(t, time.Second, l.Stats(GroupBroadcast).MaxWait)
+
+	// the canceled request did not consume the next token
+	clock.Advance(time.Second)
+	assert.NoError(t, l.Wait(context.Background(), GroupBroadcast))
+	assert.Equal(t, time.Second, l.Stats(GroupBroadcast).TotalWait)
+}
+
+func TestUnlimitedGroup(t *testing.T) {
+	l := NewRateLimiter(WithClock(newFakeClock().Now), WithLimit(GroupQuery, Limit{}))
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, l.Wait(context.Background(), GroupQuery))
+	}
+	assert.Equal(t, int64(0), l.Stats(GroupQuery).Delayed)
+}
+
+func TestObserveResponseRetryAfter(t *testing.T) {
+	clock := newFakeClock()
+	l := NewRateLimiter(WithClock(clock.Now), WithLimit(GroupQuery, Limit{}))
+
+	assert.False(t, l.ObserveResponse(GroupQuery, http.StatusOK, http.Header{"Retry-After": {"5"}}))
+	assert.True(t, l.Stats(GroupQuery).BlockedUntil.IsZero())
+
+	assert.True(t, l.ObserveResponse(GroupQuery, http.StatusTooManyRequests, http.Header{"Retry-After": {"5"}}))
+	assert.Equal(t, clock.Now().Add(5*time.Second), l.Stats(GroupQuery).BlockedUntil)
+	assert.Equal(t, context.Canceled, l.Wait(canceledContext(), GroupQuery))
+	assert.Equal(t, 5*time.Second, l.Stats(GroupQuery).MaxWait)
+
+	// a shorter period does not shorten the block
+	assert.True(t, l.ObserveResponse(GroupQuery, http.StatusTeapot, http.Header{"Retry-After": {"1"}}))
+	assert.Equal(t, clock.Now().Add(5*time.Second), l.Stats(GroupQuery).BlockedUntil)
+
+	clock.Advance(5 * time.Second)
+	assert.NoError(t, l.Wait(context.Background(), GroupQuery))
+	assert.Equal(t, int64(1), l.Stats(GroupQuery).Delayed)
+
+	// the other groups are not blocked
+	assert.True(t, l.Stats(GroupBroadcast).BlockedUntil.IsZero())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := newFakeClock().Now()
+	header := func(value string) http.Header {
+		return http.Header{"Retry-After": {value}}
+	}
+	assert.Equal(t, DefaultRetryAfter, RetryAfter(http.Header{}, now))
+	assert.Equal(t, 30*time.Second, RetryAfter(header("30"), now))
+	assert.Equal(t, time.Duration(0), RetryAfter(header("0"), now))
+	assert.Equal(t, DefaultRetryAfter, RetryAfter(header("-1"), now))
+	assert.Equal(t, DefaultRetryAfter, RetryAfter(header("soon"), now))
+	assert.Equal(t, 90*time.Second, RetryAfter(header(now.Add(90*time.Second).Format(http.TimeFormat)), now))
+	assert.Equal(t, time.Duration(0), RetryAfter(header(now.Add(-time.Minute).Format(http.TimeFormat)), now))
+}
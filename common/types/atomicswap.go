@@ -0,0 +1,38 @@
+package types
+
+// SwapStatus enum
+var SwapStatus = struct {
+	OPEN      string
+	COMPLETED string
+	EXPIRED   string
+}{
+	"OPEN",
+	"COMPLETED",
+	"EXPIRED",
+}
+
+type AtomicSwaps struct {
+	AtomicSwaps []AtomicSwap `json:"atomicSwaps"`
+	Total       int          `json:"total"`
+}
+
+// AtomicSwap def
+type AtomicSwap struct {
+	SwapID              string `json:"swapId"`
+	FromAddr            string `json:"fromAddr"`
+	ToAddr              string `json:"toAddr"`
+	OutAmount           string `json:"outAmount"`
+	InAmount            string `json:"inAmount"`
+	ExpectedIncome      string `json:"expectedIncome"`
+	RecipientOtherChain string `json:"recipientOtherChain"`
+	RandomNumberHash    string `json:"randomNumberHash"`
+	RandomNumber        string `json:"randomNumber"`
+	Status              int    `json:"status"`
+	Timestamp           int64  `json:"timestamp"`
+	BlockTimestamp      int64  `json:"blockTimestamp"`
+	ExpireHeight        int64  `json:"expireHeight"`
+	CloseTimer          int64  `json:"closeTimer"`
+	CrossChain          int    `json:"crossChain"`
+	CreateTime          int64  `json:"createTime"`
+	UpdateTime          int64  `json:"updateTime"`
+}
@@ -97,3 +97,17 @@ func (p *TransferFeeParam) Check() error {
 	}
 	return nil
 }
+
+type BlockExchangeFees struct {
+	BlockExchangeFee []BlockExchangeFee `json:"blockExchangeFee"`
+	Total            int                `json:"total"`
+}
+
+// BlockExchangeFee is the trading fee paid by an address in a block.
+type BlockExchangeFee struct {
+	Address     string `json:"address"`
+	BlockHeight int64  `json:"blockHeight"`
+	BlockTime   int64  `json:"blockTime"`
+	Fee         string `json:"fee"`
+	TradeCount  int64  `json:"tradeCount"`
+}
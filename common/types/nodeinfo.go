@@ -51,3 +51,21 @@ type NodeInfoOther struct {
 	TxIndex          string `json:"tx_index"`
 	RPCAddress       string `json:"rpc_address"`
 }
+
+type ResultValidators struct {
+	BlockHeight int64           `json:"block_height"`
+	Validators  []ValidatorInfo `json:"validators"`
+}
+
+type Peer struct {
+	ID                 string   `json:"id"`
+	OriginalListenAddr string   `json:"original_listen_addr"`
+	ListenAddr         string   `json:"listen_addr"`
+	AccessAddr         string   `json:"access_addr"`
+	StreamAddr         string   `json:"stream_addr"`
+	Network            string   `json:"network"`
+	Version            string   `json:"version"`
+	Moniker            string   `json:"moniker"`
+	Capabilities       []string `json:"capabilities"`
+	Accelerated        bool     `json:"accelerated"`
+}
@@ -9,6 +9,9 @@ import (
 const (
 	SideBuy  = "BUY"
 	SideSell = "SELL"
+
+	TxSideReceive = "RECEIVE"
+	TxSideSend    = "SEND"
 )
 
 var (
@@ -23,6 +26,9 @@ var (
 	IntervalMissingError          = errors.New("interval is required ")
 	EndTimeLessThanStartTimeError = errors.New("end time should great than start time")
 	OrderIdMissingError           = errors.New("order id is required ")
+	TxSideMisMatchError           = errors.New("Tx side is invalid ")
+	HeightOutOfRangeError         = errors.New("height out of range ")
+	SwapIdMissingError            = errors.New("swap id is required ")
 )
 
 // ClosedOrdersQuery definition
@@ -358,3 +364,229 @@ func (param *TokensQuery) Check() error {
 	}
 	return nil
 }
+
+// TransactionsQuery definition
+type TransactionsQuery struct {
+	Address     string  `json:"address"`                      // required
+	BlockHeight *int64  `json:"blockHeight,omitempty,string"` //option
+	StartTime   *int64  `json:"startTime,omitempty,string"`   //option
+	EndTime     *int64  `json:"endTime,omitempty,string"`     //option
+	Limit       *uint32 `json:"limit,omitempty,string"`       //option
+	Offset      *uint32 `json:"offset,omitempty,string"`      //option
+	Side        string  `json:"side,omitempty"`               //option, RECEIVE or SEND
+	TxAsset     string  `json:"txAsset,omitempty"`            //option
+	TxType      string  `json:"txType,omitempty"`             //option
+}
+
+func NewTransactionsQuery(address string) *TransactionsQuery {
+	return &TransactionsQuery{Address: address}
+}
+
+func (param *TransactionsQuery) Check() error {
+	if param.Address == "" {
+		return AddressMissingError
+	}
+	if param.Side != TxSideReceive && param.Side != TxSideSend && param.Side != "" {
+		return TxSideMisMatchError
+	}
+	if param.BlockHeight != nil && *param.BlockHeight <= 0 {
+		return HeightOutOfRangeError
+	}
+	if param.Limit != nil && *param.Limit <= 0 {
+		return LimitOutOfRangeError
+	}
+	if param.StartTime != nil && *param.StartTime <= 0 {
+		return StartTimeOutOfRangeError
+	}
+	if param.EndTime != nil && *param.EndTime <= 0 {
+		return EndTimeOutOfRangeError
+	}
+	if param.StartTime != nil && param.EndTime != nil && *param.StartTime > *param.EndTime {
+		return EndTimeLessThanStartTimeError
+	}
+	return nil
+}
+
+func (param *TransactionsQuery) WithBlockHeight(height int64) *TransactionsQuery {
+	param.BlockHeight = &height
+	return param
+}
+
+func (param *TransactionsQuery) WithStartTime(start int64) *TransactionsQuery {
+	param.StartTime = &start
+	return param
+}
+
+func (param *TransactionsQuery) WithEndTime(end int64) *TransactionsQuery {
+	param.EndTime = &end
+	return param
+}
+
+func (param *TransactionsQuery) WithLimit(limit uint32) *TransactionsQuery {
+	param.Limit = &limit
+	return param
+}
+
+func (param *TransactionsQuery) WithOffset(offset uint32) *TransactionsQuery {
+	param.Offset = &offset
+	return param
+}
+
+func (param *TransactionsQuery) WithSide(side string) *TransactionsQuery {
+	param.Side = side
+	return param
+}
+
+func (param *TransactionsQuery) WithTxAsset(txAsset string) *TransactionsQuery {
+	param.TxAsset = txAsset
+	return param
+}
+
+func (param *TransactionsQuery) WithTxType(txType string) *TransactionsQuery {
+	param.TxType = txType
+	return param
+}
+
+// BlockExchangeFeeQuery definition
+type BlockExchangeFeeQuery struct {
+	Address string  `json:"address,omitempty"`       //option
+	Offset  *uint32 `json:"offset,omitempty,string"` //option
+	Limit   *uint32 `json:"limit,omitempty,string"`  //option
+	Start   *int64  `json:"start,omitempty,string"`  //option
+	End     *int64  `json:"end,omitempty,string"`    //option
+	Total   int     `json:"total,string"`            //0 for not required and 1 for required; default not required, return total=-1 in response
+}
+
+func NewBlockExchangeFeeQuery(withTotal bool) *BlockExchangeFeeQuery {
+	totalQuery := 0
+	if withTotal {
+		totalQuery = 1
+	}
+	return &BlockExchangeFeeQuery{Total: totalQuery}
+}
+
+func (param *BlockExchangeFeeQuery) Check() error {
+	if param.Limit != nil && *param.Limit <= 0 {
+		return LimitOutOfRangeError
+	}
+	if param.Start != nil && *param.Start <= 0 {
+		return StartTimeOutOfRangeError
+	}
+	if param.End != nil && *param.End <= 0 {
+		return EndTimeOutOfRangeError
+	}
+	if param.Start != nil && param.End != nil && *param.Start > *param.End {
+		return EndTimeLessThanStartTimeError
+	}
+	return nil
+}
+
+func (param *BlockExchangeFeeQuery) WithAddress(address string) *BlockExchangeFeeQuery {
+	param.Address = address
+	return param
+}
+
+func (param *BlockExchangeFeeQuery) WithOffset(offset uint32) *BlockExchangeFeeQuery {
+	param.Offset = &offset
+	return param
+}
+
+func (param *BlockExchangeFeeQuery) WithLimit(limit uint32) *BlockExchangeFeeQuery {
+	param.Limit = &limit
+	return param
+}
+
+func (param *BlockExchangeFeeQuery) WithStart(start int64) *BlockExchangeFeeQuery {
+	param.Start = &start
+	return param
+}
+
+func (param *BlockExchangeFeeQuery) WithEnd(end int64) *BlockExchangeFeeQuery {
+	param.End = &end
+	return param
+}
+
+// AtomicSwapsQuery definition
+type AtomicSwapsQuery struct {
+	FromAddress string  `json:"fromAddress,omitempty"`      // fromAddress or toAddress is required
+	ToAddress   string  `json:"toAddress,omitempty"`        // fromAddress or toAddress is required
+	StartTime   *int64  `json:"startTime,omitempty,string"` //option
+	EndTime     *int64  `json:"endTime,omitempty,string"`   //option
+	Offset      *uint32 `json:"offset,omitempty,string"`    //option
+	Limit       *uint32 `json:"limit,omitempty,string"`     //option
+}
+
+func NewAtomicSwapsQuery() *AtomicSwapsQuery {
+	return &AtomicSwapsQuery{}
+}
+
+func (param *AtomicSwapsQuery) Check() error {
+	if param.FromAddress == "" && param.ToAddress == "" {
+		return AddressMissingError
+	}
+	if param.Limit != nil && *param.Limit <= 0 {
+		return LimitOutOfRangeError
+	}
+	if param.StartTime != nil && *param.StartTime <= 0 {
+		return StartTimeOutOfRangeError
+	}
+	if param.EndTime != nil && *param.EndTime <= 0 {
+		return EndTimeOutOfRangeError
+	}
+	if param.StartTime != nil && param.EndTime != nil && *param.StartTime > *param.EndTime {
+		return EndTimeLessThanStartTimeError
+	}
+	return nil
+}
+
+func (param *AtomicSwapsQuery) WithFromAddress(address string) *AtomicSwapsQuery {
+	param.FromAddress = address
+	return param
+}
+
+func (param *AtomicSwapsQuery) WithToAddress(address string) *AtomicSwapsQuery {
+	param.ToAddress = address
+	return param
+}
+
+func (param *AtomicSwapsQuery) WithStartTime(start int64) *AtomicSwapsQuery {
+	param.StartTime = &start
+	return param
+}
+
+func (param *AtomicSwapsQuery) WithEndTime(end int64) *AtomicSwapsQuery {
+	param.EndTime = &end
+	return param
+}
+
+func (param *AtomicSwapsQuery) WithOffset(offset uint32) *AtomicSwapsQuery {
+	param.Offset = &offset
+	return param
+}
+
+func (param *AtomicSwapsQuery) WithLimit(limit uint32) *AtomicSwapsQuery {
+	param.Limit = &limit
+	return param
+}
+
+// TimeLocksQuery definition
+type TimeLocksQuery struct {
+	Address string `json:"-"`                   // required, part of the path
+	Id      *int64 `json:"id,omitempty,string"` //option
+}
+
+func NewTimeLocksQuery(address string) *TimeLocksQuery {
+	return &TimeLocksQuery{Address: address}
+}
+
+func (param *TimeLocksQuery) WithId(id int64) *TimeLocksQuery {
+	param.Id = &id
+	return param
+}
+
+func (param *TimeLocksQuery) Check() error {
+	if param.Address == "" {
+		return AddressMissingError
+	}
+	return nil
+}
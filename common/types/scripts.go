@@ -5,3 +5,13 @@ type FlagOption uint64
 const (
 	TransferMemoCheckerFlag FlagOption = 0x0000000000000001
 )
+
+// IsSetIn returns whether the flag is set in the account flags.
+func (f FlagOption) IsSetIn(flags uint64) bool {
+	return flags&uint64(f) == uint64(f)
+}
+
+// IsMemoRequired returns whether transfers to an account with such flags must carry a memo.
+func IsMemoRequired(flags uint64) bool {
+	return TransferMemoCheckerFlag.IsSetIn(flags)
+}
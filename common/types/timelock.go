@@ -19,3 +19,16 @@ type QueryTimeLockParams struct {
 	Account AccAddress
 	Id      int64
 }
+
+// TimeLock is a time lock as returned by the REST API.
+type TimeLock struct {
+	Id          int64          `json:"id"`
+	Description string         `json:"description"`
+	Amount      []TimeLockCoin `json:"amount"`
+	LockTime    string         `json:"locktime"`
+}
+
+type TimeLockCoin struct {
+	Symbol string `json:"symbol"`
+	Amount Fixed8 `json:"amount"`
+}
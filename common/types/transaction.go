@@ -0,0 +1,79 @@
+package types
+
+// TxType enum
+var TxType = struct {
+	NEW_ORDER        string
+	ISSUE_TOKEN      string
+	BURN_TOKEN       string
+	LIST_TOKEN       string
+	CANCEL_ORDER     string
+	FREEZE_TOKEN     string
+	UN_FREEZE_TOKEN  string
+	TRANSFER         string
+	PROPOSAL         string
+	VOTE             string
+	MINT             string
+	DEPOSIT          string
+	CREATE_VALIDATOR string
+	REMOVE_VALIDATOR string
+	TIME_LOCK        string
+	TIME_UNLOCK      string
+	TIME_RELOCK      string
+	SET_ACCOUNT_FLAG string
+	HTL_TRANSFER     string
+	CLAIM_HTL        string
+	DEPOSIT_HTL      string
+	REFUND_HTL       string
+}{
+	"NEW_ORDER",
+	"ISSUE_TOKEN",
+	"BURN_TOKEN",
+	"LIST_TOKEN",
+	"CANCEL_ORDER",
+	"FREEZE_TOKEN",
+	"UN_FREEZE_TOKEN",
+	"TRANSFER",
+	"PROPOSAL",
+	"VOTE",
+	"MINT",
+	"DEPOSIT",
+	"CREATE_VALIDATOR",
+	"REMOVE_VALIDATOR",
+	"TIME_LOCK",
+	"TIME_UNLOCK",
+	"TIME_RELOCK",
+	"SET_ACCOUNT_FLAG",
+	"HTL_TRANSFER",
+	"CLAIM_HTL",
+	"DEPOSIT_HTL",
+	"REFUND_HTL",
+}
+
+type TxPage struct {
+	Total int  `json:"total"`
+	Tx    []Tx `json:"tx"`
+}
+
+// Tx def
+type Tx struct {
+	TxHash      string `json:"txHash"`
+	BlockHeight int64  `json:"blockHeight"`
+	TxType      string `json:"txType"`
+	TimeStamp   string `json:"timeStamp"`
+	FromAddr    string `json:"fromAddr"`
+	ToAddr      string `json:"toAddr"`
+	Value       string `json:"value"`
+	TxAsset     string `json:"txAsset"`
+	TxFee       string `json:"txFee"`
+	OrderID     string `json:"orderId,omitempty"`
+	Code        int    `json:"code"`
+	Data        string `json:"data"`
+	Memo        string `json:"memo"`
+	Source      int64  `json:"source"`
+	Sequence    int64  `json:"sequence"`
+}
+
+type BlockTxs struct {
+	BlockHeight int64 `json:"blockHeight"`
+	Tx          []Tx  `json:"tx"`
+}
@@ -2,11 +2,14 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os/exec"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -260,6 +263,24 @@ func TestReconnection(t *testing.T) {
 	cmd.Process.Release()
 }
 
+func TestBlockIterator(t *testing.T) {
+	c := defaultClient()
+	it, err := rpc.NewBlockIterator(c, int64(testTxHeight), int64(testTxHeight+10), rpc.WithConcurrency(4))
+	assert.NoError(t, err)
+	lastHeight := int64(testTxHeight - 1)
+	var buf bytes.Buffer
+	err = it.Run(context.Background(), rpc.NewMultiSink(
+		rpc.NewJSONLinesSink(&buf),
+		rpc.BlockSinkFunc(func(block *rpc.BlockInfo) error {
+			assert.Equal(t, lastHeight+1, block.Height)
+			lastHeight = block.Height
+			return nil
+		})))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(testTxHeight+10), lastHeight)
+	assert.Equal(t, 11, strings.Count(buf.String(), "\n"))
+}
+
 func TestTxSearch(t *testing.T) {
 	c := defaultClient()
 	tx, err := c.TxInfoSearch(fmt.Sprintf("tx.height=%d", testTxHeight), false, 1, 10)
@@ -393,6 +414,50 @@ func TestGetAccount(t *testing.T) {
 
 }
 
+func TestGetVerifiedStore(t *testing.T) {
+	ctypes.Network = ctypes.TestNetwork
+	c := rpc.NewRPCClient(nodeAddr, ctypes.TestNetwork)
+	acc, err := ctypes.AccAddressFromBech32(testAddress)
+	assert.NoError(t, err)
+	_, err = c.GetVerifiedCommitAccount(acc)
+	assert.Equal(t, rpc.ErrNoLightClient, err)
+
+	// the test trusts the node for the first header
+	commit, err := c.Commit(nil)
+	assert.NoError(t, err)
+	trust := rpc.TrustOptions{Height: commit.Height, Hash: commit.SignedHeader.Hash()}
+	lc, err := rpc.NewLightClient(c, "", trust, rpc.NewMemTrustStore())
+	assert.NoError(t, err)
+	c.SetLightClient(lc)
+	account, err := c.GetVerifiedCommitAccount(acc)
+	assert.NoError(t, err)
+	assert.Equal(t, acc, account.GetAddress())
+	token, err := c.GetVerifiedTokenInfo("BNB")
+	assert.NoError(t, err)
+	assert.Equal(t, "BNB", token.Symbol)
+}
+
+func TestGetStateAtHeight(t *testing.T) {
+	ctypes.Network = ctypes.TestNetwork
+	c := defaultClient()
+	acc, err := ctypes.AccAddressFromBech32(testAddress)
+	assert.NoError(t, err)
+	status, err := c.Status()
+	assert.NoError(t, err)
+	height := status.SyncInfo.LatestBlockHeight - 10
+	balances, err := c.GetBalancesAtHeight(acc, height)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, balances)
+	token, err := c.GetTokenInfoAtHeight("BNB", height)
+	assert.NoError(t, err)
+	assert.Equal(t, "BNB", token.Symbol)
+	pairs, err := c.GetTradingPairsAtHeight(0, 10, height)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pairs)
+	_, err = c.GetBalancesAtHeight(acc, 1)
+	assert.True(t, errors.Is(err, rpc.ErrHeightPruned))
+}
+
 func TestGetBalances(t *testing.T) {
 	ctypes.Network = ctypes.TestNetwork
 	c := defaultClient()
@@ -43,6 +43,11 @@ func TestTransProcess(t *testing.T) {
 	fmt.Printf("Set account flags: %v \n", addFlags)
 	accn,_:=client.GetAccount(client.GetKeyManager().GetAddr().String())
 	fmt.Println(accn)
+	nodeInfo, err := client.GetNodeInfo()
+	assert.NoError(t, err)
+	memoChecked := transaction.NewClient(nodeInfo.NodeInfo.Network, keyManager, client, client, transaction.WithMemoCheck())
+	_, err = memoChecked.SendToken([]msg.Transfer{{testAccount1, []ctypes.Coin{{nativeSymbol, 100000000}}}}, true)
+	assert.Error(t, err)
 	setFlags, err := client.SetAccountFlags(0, true)
 	assert.NoError(t, err)
 	fmt.Printf("Set account flags: %v \n", setFlags)
@@ -93,6 +98,35 @@ func TestTransProcess(t *testing.T) {
 	assert.NoError(t, err)
 	fmt.Printf("Get time: %v \n", time)
 
+	//-----  Get Transactions  -----------
+	txs, err := client.GetTransactions(ctypes.NewTransactionsQuery(testAccount1.String()).WithLimit(10))
+	assert.NoError(t, err)
+	fmt.Printf("GetTransactions: %v \n", txs)
+
+	//-----  Get Block Exchange Fee  -----------
+	blockFees, err := client.GetBlockExchangeFee(ctypes.NewBlockExchangeFeeQuery(true).WithAddress(testAccount1.String()))
+	assert.NoError(t, err)
+	fmt.Printf("GetBlockExchangeFee: %v \n", blockFees)
+
+	//-----  Get Validators, Peers and Fees  -----------
+	validators, err := client.GetValidators()
+	assert.NoError(t, err)
+	assert.True(t, len(validators.Validators) > 0)
+	peers, err := client.GetPeers()
+	assert.NoError(t, err)
+	assert.True(t, len(peers) > 0)
+	fees, err := client.GetFees()
+	assert.NoError(t, err)
+	assert.True(t, len(fees) > 0)
+
+	//-----  Get Atomic Swaps and Time Locks  -----------
+	swaps, err := client.GetAtomicSwaps(ctypes.NewAtomicSwapsQuery().WithFromAddress(testAccount1.String()))
+	assert.NoError(t, err)
+	fmt.Printf("GetAtomicSwaps: %v \n", swaps)
+	timeLocks, err := client.GetTimeLocks(ctypes.NewTimeLocksQuery(testAccount1.String()))
+	assert.NoError(t, err)
+	fmt.Printf("GetTimeLocks: %v \n", timeLocks)
+
 	//-----   time lock  -----------
 	lockResult, err := client.TimeLock("test lock", ctypes.Coins{{"BNB", 100000000}}, int64(time2.Now().Add(65*time2.Second).Unix()), true)
 	assert.NoError(t, err)
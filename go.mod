@@ -1,5 +1,7 @@
 module github.com/binance-chain/go-sdk
 
+go 1.13
+
 require (
 	github.com/binance-chain/ledger-cosmos-go v0.9.9-binance.1
 	github.com/btcsuite/btcd v0.0.0-20181013004428-67e573d211ac
@@ -25,6 +27,7 @@ require (
 	github.com/zondax/hid v0.9.0 // indirect
 	github.com/zondax/ledger-go v0.9.0 // indirect
 	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
+	golang.org/x/text v0.3.0
 	google.golang.org/grpc v1.19.1 // indirect
 	gopkg.in/resty.v1 v1.10.3
 )
@@ -0,0 +1,111 @@
+package keys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tendermint/tendermint/crypto"
+
+	ctypes "github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+// Errors of the chain pinned KeyManager, test them with errors.Is.
+var (
+	ErrChainIDNotAllowed = errors.New("chain id is not allowed by the key manager")
+	ErrNetworkMismatch   = errors.New("network is not the one of the key manager")
+)
+
+type chainKeyManager struct {
+	KeyManager
+	network  ctypes.ChainNetwork
+	chainIDs map[string]bool
+}
+
+// NewChainKeyManager wraps km so that it only signs the messages of chainIDs,
+// and only while ctypes.Network is network. It keeps a workflow written for a
+// testnet from being replayed on mainnet.
+func NewChainKeyManager(km KeyManager, network ctypes.ChainNetwork, chainIDs ...string) KeyManager {
+	m := &chainKeyManager{KeyManager: km, network: network, chainIDs: make(map[string]bool, len(chainIDs))}
+	for _, chainID := range chainIDs {
+		m.chainIDs[chainID] = true
+	}
+	return m
+}
+
+func (m *chainKeyManager) Sign(signMsg tx.StdSignMsg) ([]byte, error) {
+	if err := m.check(signMsg.ChainID); err != nil {
+		return nil, err
+	}
+	return m.KeyManager.Sign(signMsg)
+}
+
+func (m *chainKeyManager) check(chainID string) error {
+	if ctypes.Network != m.network {
+		return fmt.Errorf("%w: the addresses are %s ones, the key manager is pinned to %s", ErrNetworkMismatch,
+			ctypes.Network.Bech32Prefixes(), m.network.Bech32Prefixes())
+	}
+	if !m.chainIDs[chainID] {
+		allowed := make([]string, 0, len(m.chainIDs))
+		for chainID := range m.chainIDs {
+			allowed = append(allowed, chainID)
+		}
+		sort.Strings(allowed)
+		return fmt.Errorf("%w: got %q, allowed %s", ErrChainIDNotAllowed, chainID, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+func (m *chainKeyManager) GetPrivKey() crypto.PrivKey {
+	return chainPrivKey{privKey: m.KeyManager.GetPrivKey(), m: m}
+}
+
+func (m *chainKeyManager) ExportAsMnemonic() (string, error) {
+	return "", fmt.Errorf("export is disabled by the chain pinned key manager")
+}
+
+func (m *chainKeyManager) ExportAsPrivateKey() (string, error) {
+	return "", fmt.Errorf("export is disabled by the chain pinned key manager")
+}
+
+func (m *chainKeyManager) ExportAsKeyStore(password string, options ...KeyStoreOption) (*EncryptedKeyJSON, error) {
+	return nil, fmt.Errorf("export is disabled by the chain pinned key manager")
+}
+
+// chainPrivKey only signs the sign bytes of a message of the allowed chains,
+// the chain ID is read from the bytes. The raw key would sign anything.
+type chainPrivKey struct {
+	privKey crypto.PrivKey
+	m       *chainKeyManager
+}
+
+func (pk chainPrivKey) Bytes() []byte {
+	return nil
+}
+
+func (pk chainPrivKey) Sign(msg []byte) ([]byte, error) {
+	var signMsg struct {
+		ChainID string `json:"chain_id"`
+	}
+	if err := json.Unmarshal(msg, &signMsg); err != nil {
+		return nil, fmt.Errorf("%w: the sign bytes are not a sign message", ErrChainIDNotAllowed)
+	}
+	if err := pk.m.check(signMsg.ChainID); err != nil {
+		return nil, err
+	}
+	return pk.privKey.Sign(msg)
+}
+
+func (pk chainPrivKey) PubKey() crypto.PubKey {
+	return pk.privKey.PubKey()
+}
+
+func (pk chainPrivKey) Equals(other crypto.PrivKey) bool {
+	if o, ok := other.(chainPrivKey); ok {
+		return pk.privKey.Equals(o.privKey)
+	}
+	return pk.privKey.Equals(other)
+}
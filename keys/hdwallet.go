@@ -0,0 +1,142 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+
+	ctypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+const DefaultGapLimit = 20
+
+// ErrHDWalletMnemonicExport is returned by the ExportAsMnemonic of the key
+// managers of an HDWallet: the mnemonic alone is not enough to recover them.
+var ErrHDWalletMnemonicExport = errors.New("key manager is derived from an HD wallet, its mnemonic would recover another key")
+
+// HDWallet holds the master key derived once from a mnemonic, and derives the
+// accounts m / 44' / 714' / account' / 0 / address_index on demand.
+type HDWallet struct {
+	masterPriv [32]byte
+	chainCode  [32]byte
+}
+
+// DerivedAddress is an address together with the BIP44 indexes it is derived from.
+type DerivedAddress struct {
+	Account uint32
+	Index   uint32
+	Address ctypes.AccAddress
+}
+
+// AddressUsageFunc tells whether an address has ever been used, usually by
+// querying its balances or its sequence.
+type AddressUsageFunc func(addr ctypes.AccAddress) (bool, error)
+
+// BalanceClient is the account query of the API client, query.QueryClient.
+type BalanceClient interface {
+	GetAccount(address string) (*ctypes.BalanceAccount, error)
+}
+
+// AccountClient is the account query of the node client, rpc.DexClient.
+type AccountClient interface {
+	GetAccount(addr ctypes.AccAddress) (ctypes.Account, error)
+}
+
+// UsedByBalance returns an AddressUsageFunc querying the account of the
+// address through the API: it is used once it has balances or a sequence.
+func UsedByBalance(client BalanceClient) AddressUsageFunc {
+	return func(addr ctypes.AccAddress) (bool, error) {
+		acc, err := client.GetAccount(addr.String())
+		if err != nil {
+			return false, err
+		}
+		return len(acc.Balances) > 0 || acc.Sequence > 0, nil
+	}
+}
+
+// UsedByAccount returns an AddressUsageFunc querying the account of the
+// address on a node: it is used once it exists.
+func UsedByAccount(client AccountClient) AddressUsageFunc {
+	return func(addr ctypes.AccAddress) (bool, error) {
+		acc, err := client.GetAccount(addr)
+		if err != nil {
+			return false, err
+		}
+		return acc != nil && (len(acc.GetCoins()) > 0 || acc.GetSequence() > 0), nil
+	}
+}
+
+// NewHDWallet derives the master key of mnemonic. The passphrase and word list
+// options are honoured, the others are ignored.
+func NewHDWallet(mnemonic string, options ...MnemonicOption) (*HDWallet, error) {
+	o := newMnemonicOptions(options...)
+	seed, err := MnemonicToSeed(mnemonic, o.passphrase, o.wordlist)
+	if err != nil {
+		return nil, err
+	}
+	masterPriv, ch := ComputeMastersFromSeed(seed)
+	return &HDWallet{masterPriv: masterPriv, chainCode: ch}, nil
+}
+
+// KeyManager returns the KeyManager of the given account and address index.
+// Its ExportAsMnemonic fails with ErrHDWalletMnemonicExport, export the private
+// key or a keystore instead.
+func (w *HDWallet) KeyManager(account, index uint32) (KeyManager, error) {
+	k := keyManager{hdDerived: true}
+	err := k.recoveryFromMasterKey(w.masterPriv, w.chainCode, NewBinanceBIP44Params(account, index).String())
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Address returns the address of the given account and address index.
+func (w *HDWallet) Address(account, index uint32) (ctypes.AccAddress, error) {
+	km, err := w.KeyManager(account, index)
+	if err != nil {
+		return nil, err
+	}
+	return km.GetAddr(), nil
+}
+
+// Addresses returns count addresses of account, starting at address index from.
+func (w *HDWallet) Addresses(account, from, count uint32) ([]DerivedAddress, error) {
+	addrs := make([]DerivedAddress, 0, count)
+	for index := from; index < from+count; index++ {
+		addr, err := w.Address(account, index)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, DerivedAddress{Account: account, Index: index, Address: addr})
+	}
+	return addrs, nil
+}
+
+// ScanUsedAddresses walks the address indexes of account and returns the used
+// ones. The scan stops after gapLimit consecutive unused addresses, as BIP44
+// wallets do. A gapLimit of 0 means DefaultGapLimit.
+func (w *HDWallet) ScanUsedAddresses(account uint32, gapLimit uint32, isUsed AddressUsageFunc) ([]DerivedAddress, error) {
+	if isUsed == nil {
+		return nil, fmt.Errorf("address usage function is missing")
+	}
+	if gapLimit == 0 {
+		gapLimit = DefaultGapLimit
+	}
+	used := make([]DerivedAddress, 0)
+	for index, gap := uint32(0), uint32(0); gap < gapLimit; index++ {
+		addr, err := w.Address(account, index)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := isUsed(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check address %s: %s", addr.String(), err.Error())
+		}
+		if ok {
+			used = append(used, DerivedAddress{Account: account, Index: index, Address: addr})
+			gap = 0
+		} else {
+			gap++
+		}
+	}
+	return used, nil
+}
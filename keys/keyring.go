@@ -0,0 +1,343 @@
+package keys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/binance-chain/go-sdk/common/ledger"
+)
+
+const (
+	keyStoreFileSuffix = ".json"
+	ledgerFileSuffix   = ".ledger.json"
+)
+
+var (
+	ErrKeyNotFound = errors.New("key not found in keyring")
+	ErrKeyExists   = errors.New("a key with the same name already exists in keyring")
+
+	isKeyNameFunc = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`).MatchString
+)
+
+type KeyType string
+
+const (
+	// LocalKey entries hold the private key, encrypted in the keystore format.
+	LocalKey KeyType = "local"
+	// LedgerKey entries only hold the derivation path of a key kept on a Ledger device.
+	LedgerKey KeyType = "ledger"
+)
+
+// KeyInfo describes a keyring entry without unlocking it.
+type KeyInfo struct {
+	Name    string                `json:"name"`
+	Type    KeyType               `json:"type"`
+	Address string                `json:"address"`
+	Path    ledger.DerivationPath `json:"path,omitempty"`
+}
+
+// Keyring stores named keys in a directory. Every local key is a keystore file
+// "<name>.json" that NewKeyStoreKeyManager can load as well, every Ledger key
+// is a "<name>.ledger.json" file holding its address and derivation path.
+type Keyring struct {
+	mtx             sync.Mutex
+	dir             string
+	keyStoreOptions []KeyStoreOption
+}
+
+type KeyringOption func(*Keyring)
+
+// WithKeyringKeyStoreOptions sets the KDF used to encrypt the keys added to the keyring.
+func WithKeyringKeyStoreOptions(options ...KeyStoreOption) KeyringOption {
+	return func(r *Keyring) {
+		r.keyStoreOptions = options
+	}
+}
+
+// NewKeyring opens the keyring in dir, creating the directory if needed.
+func NewKeyring(dir string, options ...KeyringOption) (*Keyring, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	r := &Keyring{dir: dir}
+	for _, option := range options {
+		option(r)
+	}
+	return r, nil
+}
+
+// List returns every key of the keyring, sorted by name.
+func (r *Keyring) List() ([]KeyInfo, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	files, err := ioutil.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]KeyInfo, 0, len(files))
+	for _, f := range files {
+		name, ok := keyNameOf(f.Name())
+		if f.IsDir() || !ok {
+			continue
+		}
+		info, _, err := r.load(name)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Get returns the description of the key name.
+func (r *Keyring) Get(name string) (*KeyInfo, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	info, _, err := r.load(name)
+	return info, err
+}
+
+// Add encrypts the private key of km with password and stores it as name.
+func (r *Keyring) Add(name, password string, km KeyManager) (*KeyInfo, error) {
+	if password == "" {
+		return nil, fmt.Errorf("Password is missing ")
+	}
+	keyJSON, err := km.ExportAsKeyStore(password, r.keyStoreOptions...)
+	if err != nil {
+		return nil, err
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if err := r.checkNew(name); err != nil {
+		return nil, err
+	}
+	if err := writeKeyFile(r.keyStoreFile(name), keyJSON); err != nil {
+		return nil, err
+	}
+	return &KeyInfo{Name: name, Type: LocalKey, Address: keyJSON.Address}, nil
+}
+
+// AddLedger stores a reference to the key at path on the connected Ledger device.
+func (r *Keyring) AddLedger(name string, path ledger.DerivationPath) (*KeyInfo, error) {
+	km, err := NewLedgerKeyManager(path)
+	if err != nil {
+		return nil, err
+	}
+	info := &KeyInfo{Name: name, Type: LedgerKey, Address: km.GetAddr().String(), Path: path}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if err := r.checkNew(name); err != nil {
+		return nil, err
+	}
+	if err := writeKeyFile(r.ledgerFile(name), info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ImportKeyStore stores an existing keystore as name. It keeps its own password,
+// which is checked before the import.
+func (r *Keyring) ImportKeyStore(name string, keyJSON []byte, auth string) (*KeyInfo, error) {
+	var encryptedKey EncryptedKeyJSON
+	if err := json.Unmarshal(keyJSON, &encryptedKey); err != nil {
+		return nil, err
+	}
+	k := keyManager{}
+	if err := k.recoveryFromEncryptedKey(&encryptedKey, auth); err != nil {
+		return nil, err
+	}
+	encryptedKey.Address = k.GetAddr().String()
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if err := r.checkNew(name); err != nil {
+		return nil, err
+	}
+	if err := writeKeyFile(r.keyStoreFile(name), &encryptedKey); err != nil {
+		return nil, err
+	}
+	return &KeyInfo{Name: name, Type: LocalKey, Address: encryptedKey.Address}, nil
+}
+
+// ExportKeyStore returns the keystore of the local key name, still encrypted.
+func (r *Keyring) ExportKeyStore(name string) (*EncryptedKeyJSON, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	info, keyJSON, err := r.load(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.Type != LocalKey {
+		return nil, fmt.Errorf("key %s is kept on a Ledger device and can't be exported", name)
+	}
+	return keyJSON, nil
+}
+
+// KeyManager unlocks the key name. The password is ignored for Ledger keys.
+func (r *Keyring) KeyManager(name, password string) (KeyManager, error) {
+	r.mtx.Lock()
+	info, keyJSON, err := r.load(name)
+	r.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if info.Type == LedgerKey {
+		km, err := NewLedgerKeyManager(info.Path)
+		if err != nil {
+			return nil, err
+		}
+		if km.GetAddr().String() != info.Address {
+			return nil, fmt.Errorf("the Ledger device does not hold key %s, expected address %s but got %s", name, info.Address, km.GetAddr().String())
+		}
+		return km, nil
+	}
+	k := keyManager{}
+	if err := k.recoveryFromEncryptedKey(keyJSON, password); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Rename renames the key oldName to newName.
+func (r *Keyring) Rename(oldName, newName string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	info, _, err := r.load(oldName)
+	if err != nil {
+		return err
+	}
+	if err := r.checkNew(newName); err != nil {
+		return err
+	}
+	if info.Type == LedgerKey {
+		info.Name = newName
+		if err := writeKeyFile(r.ledgerFile(newName), info); err != nil {
+			return err
+		}
+		return os.Remove(r.ledgerFile(oldName))
+	}
+	return os.Rename(r.keyStoreFile(oldName), r.keyStoreFile(newName))
+}
+
+// Delete removes the key name. The password of local keys is checked first.
+func (r *Keyring) Delete(name, password string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	info, keyJSON, err := r.load(name)
+	if err != nil {
+		return err
+	}
+	if info.Type == LedgerKey {
+		return os.Remove(r.ledgerFile(name))
+	}
+	k := keyManager{}
+	if err := k.recoveryFromEncryptedKey(keyJSON, password); err != nil {
+		return err
+	}
+	return os.Remove(r.keyStoreFile(name))
+}
+
+// ChangePassword encrypts the local key name again with newPassword.
+func (r *Keyring) ChangePassword(name, oldPassword, newPassword string) error {
+	if newPassword == "" {
+		return fmt.Errorf("Password is missing ")
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	info, keyJSON, err := r.load(name)
+	if err != nil {
+		return err
+	}
+	if info.Type != LocalKey {
+		return fmt.Errorf("key %s is kept on a Ledger device and has no password", name)
+	}
+	k := keyManager{}
+	if err := k.recoveryFromEncryptedKey(keyJSON, oldPassword); err != nil {
+		return err
+	}
+	newKeyJSON, err := k.ExportAsKeyStore(newPassword, r.keyStoreOptions...)
+	if err != nil {
+		return err
+	}
+	newKeyJSON.Id = keyJSON.Id
+	return writeKeyFile(r.keyStoreFile(name), newKeyJSON)
+}
+
+func (r *Keyring) keyStoreFile(name string) string {
+	return filepath.Join(r.dir, name+keyStoreFileSuffix)
+}
+
+func (r *Keyring) ledgerFile(name string) string {
+	return filepath.Join(r.dir, name+ledgerFileSuffix)
+}
+
+// load reads the entry name, the keystore is nil for Ledger keys.
+func (r *Keyring) load(name string) (*KeyInfo, *EncryptedKeyJSON, error) {
+	if !isKeyNameFunc(name) {
+		return nil, nil, fmt.Errorf("invalid key name %q", name)
+	}
+	if bz, err := ioutil.ReadFile(r.ledgerFile(name)); err == nil {
+		var info KeyInfo
+		if err := json.Unmarshal(bz, &info); err != nil {
+			return nil, nil, fmt.Errorf("failed to read key %s: %s", name, err.Error())
+		}
+		info.Name = name
+		info.Type = LedgerKey
+		return &info, nil, nil
+	} else if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	bz, err := ioutil.ReadFile(r.keyStoreFile(name))
+	if os.IsNotExist(err) {
+		return nil, nil, ErrKeyNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+	var keyJSON EncryptedKeyJSON
+	if err := json.Unmarshal(bz, &keyJSON); err != nil {
+		return nil, nil, fmt.Errorf("failed to read key %s: %s", name, err.Error())
+	}
+	return &KeyInfo{Name: name, Type: LocalKey, Address: keyJSON.Address}, &keyJSON, nil
+}
+
+func (r *Keyring) checkNew(name string) error {
+	_, _, err := r.load(name)
+	if err == nil {
+		return ErrKeyExists
+	}
+	if err != ErrKeyNotFound {
+		return err
+	}
+	return nil
+}
+
+func keyNameOf(fileName string) (string, bool) {
+	var name string
+	if strings.HasSuffix(fileName, ledgerFileSuffix) {
+		name = strings.TrimSuffix(fileName, ledgerFileSuffix)
+	} else if strings.HasSuffix(fileName, keyStoreFileSuffix) {
+		name = strings.TrimSuffix(fileName, keyStoreFileSuffix)
+	}
+	return name, isKeyNameFunc(name)
+}
+
+// writeKeyFile replaces file atomically, so that a crash never leaves a truncated key behind.
+func writeKeyFile(file string, v interface{}) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile := file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, bz, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
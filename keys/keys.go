@@ -1,15 +1,12 @@
 package keys
 
 import (
-	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"strings"
 
 	"github.com/cosmos/go-bip39"
-	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/tendermint/tendermint/crypto/secp256k1"
@@ -34,7 +31,7 @@ type KeyManager interface {
 
 	ExportAsMnemonic() (string, error)
 	ExportAsPrivateKey() (string, error)
-	ExportAsKeyStore(password string) (*EncryptedKeyJSON, error)
+	ExportAsKeyStore(password string, options ...KeyStoreOption) (*EncryptedKeyJSON, error)
 }
 
 func NewMnemonicKeyManager(mnemonic string) (KeyManager, error) {
@@ -73,9 +70,15 @@ type keyManager struct {
 	privKey  crypto.PrivKey
 	addr     ctypes.AccAddress
 	mnemonic string
+	// hdDerived is set for the keys of an HDWallet, whose mnemonic alone would
+	// recover another key.
+	hdDerived bool
 }
 
 func (m *keyManager) ExportAsMnemonic() (string, error) {
+	if m.hdDerived {
+		return "", ErrHDWalletMnemonicExport
+	}
 	if m.mnemonic == "" {
 		return "", fmt.Errorf("This key manager is not recover from mnemonic or anto generated ")
 	}
@@ -90,8 +93,8 @@ func (m *keyManager) ExportAsPrivateKey() (string, error) {
 	return hex.EncodeToString(secpPrivateKey[:]), nil
 }
 
-func (m *keyManager) ExportAsKeyStore(password string) (*EncryptedKeyJSON, error) {
-	return generateKeyStore(m.GetPrivKey(), password)
+func (m *keyManager) ExportAsKeyStore(password string, options ...KeyStoreOption) (*EncryptedKeyJSON, error) {
+	return generateKeyStore(m.GetPrivKey(), password, options...)
 }
 
 func NewKeyManager() (KeyManager, error) {
@@ -107,28 +110,32 @@ func NewKeyManager() (KeyManager, error) {
 }
 
 func (m *keyManager) recoveryFromMnemonic(mnemonic, keyPath string) error {
-	words := strings.Split(mnemonic, " ")
-	if len(words) != 12 && len(words) != 24 {
-		return fmt.Errorf("mnemonic length should either be 12 or 24")
-	}
-	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, defaultBIP39Passphrase)
+	seed, err := seedFromMnemonic(mnemonic)
 	if err != nil {
 		return err
 	}
 	// create master key and derive first key:
 	masterPriv, ch := ComputeMastersFromSeed(seed)
+	if err := m.recoveryFromMasterKey(masterPriv, ch, keyPath); err != nil {
+		return err
+	}
+	m.mnemonic = mnemonic
+	return nil
+}
+
+func seedFromMnemonic(mnemonic string) ([]byte, error) {
+	return MnemonicToSeed(mnemonic, defaultBIP39Passphrase, EnglishWordlist)
+}
+
+func (m *keyManager) recoveryFromMasterKey(masterPriv, ch [32]byte, keyPath string) error {
 	derivedPriv, err := DerivePrivateKeyForPath(masterPriv, ch, keyPath)
 	if err != nil {
 		return err
 	}
 	priKey := secp256k1.PrivKeySecp256k1(derivedPriv)
 	addr := ctypes.AccAddress(priKey.PubKey().Address())
-	if err != nil {
-		return err
-	}
 	m.addr = addr
 	m.privKey = priKey
-	m.mnemonic = mnemonic
 	return nil
 }
 
@@ -145,7 +152,14 @@ func (m *keyManager) recoveryFromKeyStore(keystoreFile string, auth string) erro
 	if err != nil {
 		return err
 	}
-	keyBytes, err := decryptKey(&encryptedKey, auth)
+	return m.recoveryFromEncryptedKey(&encryptedKey, auth)
+}
+
+func (m *keyManager) recoveryFromEncryptedKey(encryptedKey *EncryptedKeyJSON, auth string) error {
+	if auth == "" {
+		return fmt.Errorf("Password is missing ")
+	}
+	keyBytes, err := decryptKey(encryptedKey, auth)
 	if err != nil {
 		return err
 	}
@@ -238,7 +252,11 @@ func (m *keyManager) makeSignature(msg tx.StdSignMsg) (sig tx.StdSignature, err
 	}, nil
 }
 
-func generateKeyStore(privateKey crypto.PrivKey, password string) (*EncryptedKeyJSON, error) {
+func generateKeyStore(privateKey crypto.PrivKey, password string, options ...KeyStoreOption) (*EncryptedKeyJSON, error) {
+	cfg := keyStoreConfig{kdf: KDFPBKDF2, c: DefaultPBKDF2C}
+	for _, option := range options {
+		option(&cfg)
+	}
 	addr := ctypes.AccAddress(privateKey.PubKey().Address())
 	salt, err := common.GenerateRandomBytes(32)
 	if err != nil {
@@ -248,14 +266,16 @@ func generateKeyStore(privateKey crypto.PrivKey, password string) (*EncryptedKey
 	if err != nil {
 		return nil, err
 	}
-	scryptParamsJSON := make(map[string]interface{}, 4)
-	scryptParamsJSON["prf"] = "hmac-sha256"
-	scryptParamsJSON["dklen"] = 32
-	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
-	scryptParamsJSON["c"] = 262144
+	kdfParamsJSON, err := cfg.kdfParams(salt)
+	if err != nil {
+		return nil, err
+	}
 
 	cipherParamsJSON := cipherparamsJSON{IV: hex.EncodeToString(iv)}
-	derivedKey := pbkdf2.Key([]byte(password), salt, 262144, 32, sha256.New)
+	derivedKey, err := getKDFKey(CryptoJSON{KDF: cfg.kdf, KDFParams: kdfParamsJSON}, password)
+	if err != nil {
+		return nil, err
+	}
 	encryptKey := derivedKey[:32]
 	secpPrivateKey, ok := privateKey.(secp256k1.PrivKeySecp256k1)
 	if !ok {
@@ -279,8 +299,8 @@ func generateKeyStore(privateKey crypto.PrivKey, password string) (*EncryptedKey
 		Cipher:       "aes-256-ctr",
 		CipherText:   hex.EncodeToString(cipherText),
 		CipherParams: cipherParamsJSON,
-		KDF:          "pbkdf2",
-		KDFParams:    scryptParamsJSON,
+		KDF:          cfg.kdf,
+		KDFParams:    kdfParamsJSON,
 		MAC:          hex.EncodeToString(mac),
 	}
 	return &EncryptedKeyJSON{
@@ -2,13 +2,22 @@ package keys
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/http/httptest"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	ledgergo "github.com/binance-chain/ledger-cosmos-go"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/unicode/norm"
 
 	ctypes "github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/go-sdk/types/msg"
@@ -192,6 +201,99 @@ func TestExportAsKeyStoreNoError(t *testing.T) {
 	assert.True(t, bytes.Equal(encryPlain1, encryPlain2))
 }
 
+func TestExportAsScryptKeyStoreNoError(t *testing.T) {
+	defer os.Remove("TestGenerateScryptKeyStoreNoError.json")
+	km, err := NewKeyManager()
+	assert.NoError(t, err)
+	keyJSON, err := km.ExportAsKeyStore("testpassword", WithScrypt(LightScryptN, ScryptR, LightScryptP))
+	assert.NoError(t, err)
+	assert.Equal(t, KDFScrypt, keyJSON.Crypto.KDF)
+	bz, err := json.Marshal(keyJSON)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile("TestGenerateScryptKeyStoreNoError.json", bz, 0660)
+	assert.NoError(t, err)
+	newkm, err := NewKeyStoreKeyManager("TestGenerateScryptKeyStoreNoError.json", "testpassword")
+	assert.NoError(t, err)
+	assert.Equal(t, km.GetAddr().String(), newkm.GetAddr().String())
+	_, err = NewKeyStoreKeyManager("TestGenerateScryptKeyStoreNoError.json", "wrongpassword")
+	assert.Equal(t, ErrDecrypt, err)
+
+	_, err = km.ExportAsKeyStore("testpassword", WithScrypt(1000, ScryptR, LightScryptP))
+	assert.Error(t, err)
+}
+
+func TestWeb3KeyStoreNoError(t *testing.T) {
+	// test vectors of the Web3 Secret Storage Definition
+	vectors := map[string]string{
+		"pbkdf2": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2",
+			"kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},
+			"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},
+			"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		"scrypt": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt",
+			"kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},
+			"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},
+			"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+	}
+	dir, err := ioutil.TempDir("", "web3keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for kdf, keyJSON := range vectors {
+		file := filepath.Join(dir, kdf+".json")
+		assert.NoError(t, ioutil.WriteFile(file, []byte(keyJSON), 0600))
+		km, err := NewKeyStoreKeyManager(file, "testpassword")
+		assert.NoError(t, err, kdf)
+		if err != nil {
+			continue
+		}
+		pk, err := km.ExportAsPrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d", pk, kdf)
+		_, err = NewKeyStoreKeyManager(file, "wrongpassword")
+		assert.Equal(t, ErrDecrypt, err, kdf)
+	}
+}
+
+func TestKeyStoreMalformedKDFParams(t *testing.T) {
+	km, err := NewKeyManager()
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cases := map[string]func(params map[string]interface{}){
+		"scrypt without n":   func(params map[string]interface{}) { delete(params, "n") },
+		"scrypt without r":   func(params map[string]interface{}) { delete(params, "r") },
+		"scrypt without p":   func(params map[string]interface{}) { delete(params, "p") },
+		"scrypt string n":    func(params map[string]interface{}) { params["n"] = "4096" },
+		"scrypt fraction p":  func(params map[string]interface{}) { params["p"] = 1.5 },
+		"without salt":       func(params map[string]interface{}) { delete(params, "salt") },
+		"number salt":        func(params map[string]interface{}) { params["salt"] = 12 },
+		"without dklen":      func(params map[string]interface{}) { delete(params, "dklen") },
+		"pbkdf2 without prf": func(params map[string]interface{}) { delete(params, "prf") },
+		"pbkdf2 without c":   func(params map[string]interface{}) { delete(params, "c") },
+	}
+	for name, change := range cases {
+		var options []KeyStoreOption
+		if strings.HasPrefix(name, "scrypt") {
+			options = append(options, WithScrypt(LightScryptN, ScryptR, LightScryptP))
+		}
+		keyJSON, err := km.ExportAsKeyStore("testpassword", options...)
+		assert.NoError(t, err)
+		change(keyJSON.Crypto.KDFParams)
+		bz, err := json.Marshal(keyJSON)
+		assert.NoError(t, err)
+		file := filepath.Join(dir, "keystore.json")
+		assert.NoError(t, ioutil.WriteFile(file, bz, 0600))
+
+		assert.NotPanics(t, func() {
+			_, err = NewKeyStoreKeyManager(file, "testpassword")
+		}, name)
+		assert.Error(t, err, name)
+	}
+}
+
 func TestExportAsMnemonicNoError(t *testing.T) {
 	km, err := NewKeyManager()
 	assert.NoError(t, err)
@@ -233,3 +335,502 @@ func TestExportAsMnemonicyError(t *testing.T) {
 	_, err = km.ExportAsMnemonic()
 	assert.Error(t, err)
 }
+
+func TestHDWalletDeriveNoError(t *testing.T) {
+	mnemonic := "bottom quick strong ranch section decide pepper broken oven demand coin run jacket curious business achieve mule bamboo remain vote kid rigid bench rubber"
+	wallet, err := NewHDWallet(mnemonic)
+	assert.NoError(t, err)
+	km, err := wallet.KeyManager(0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "bnb1ddt3ls9fjcd8mh69ujdg3fxc89qle2a7km33aa", km.GetAddr().String())
+
+	pathKm, err := NewMnemonicPathKeyManager(mnemonic, "1'/0/1")
+	assert.NoError(t, err)
+	addr, err := wallet.Address(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, pathKm.GetAddr().String(), addr.String())
+
+	addrs, err := wallet.Addresses(1, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(addrs))
+	assert.Equal(t, addr.String(), addrs[1].Address.String())
+
+	// the wallet mnemonic alone would recover account 0 / index 0
+	subKm, err := wallet.KeyManager(1, 1)
+	assert.NoError(t, err)
+	_, err = subKm.ExportAsMnemonic()
+	assert.Equal(t, ErrHDWalletMnemonicExport, err)
+	_, err = km.ExportAsMnemonic()
+	assert.Equal(t, ErrHDWalletMnemonicExport, err)
+	_, err = subKm.ExportAsPrivateKey()
+	assert.NoError(t, err)
+}
+
+func TestHDWalletScanUsedAddresses(t *testing.T) {
+	mnemonic := "bottom quick strong ranch section decide pepper broken oven demand coin run jacket curious business achieve mule bamboo remain vote kid rigid bench rubber"
+	wallet, err := NewHDWallet(mnemonic)
+	assert.NoError(t, err)
+	usedAddrs := map[string]bool{}
+	for _, index := range []uint32{0, 3, 25} {
+		addr, err := wallet.Address(0, index)
+		assert.NoError(t, err)
+		usedAddrs[addr.String()] = true
+	}
+	used, err := wallet.ScanUsedAddresses(0, 20, func(addr ctypes.AccAddress) (bool, error) {
+		return usedAddrs[addr.String()], nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(used))
+	assert.Equal(t, uint32(3), used[1].Index)
+
+	used, err = wallet.ScanUsedAddresses(0, 30, func(addr ctypes.AccAddress) (bool, error) {
+		return usedAddrs[addr.String()], nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(used))
+}
+
+type fakeBalanceClient struct {
+	accounts map[string]*ctypes.BalanceAccount
+	err      error
+}
+
+func (c fakeBalanceClient) GetAccount(address string) (*ctypes.BalanceAccount, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if acc, ok := c.accounts[address]; ok {
+		return acc, nil
+	}
+	// the API answers an empty account for unknown addresses
+	return &ctypes.BalanceAccount{}, nil
+}
+
+type fakeAccountClient map[string]ctypes.Account
+
+func (c fakeAccountClient) GetAccount(addr ctypes.AccAddress) (ctypes.Account, error) {
+	return c[addr.String()], nil
+}
+
+func TestHDWalletScanByBalance(t *testing.T) {
+	mnemonic := "bottom quick strong ranch section decide pepper broken oven demand coin run jacket curious business achieve mule bamboo remain vote kid rigid bench rubber"
+	wallet, err := NewHDWallet(mnemonic)
+	assert.NoError(t, err)
+	addrs, err := wallet.Addresses(0, 0, 8)
+	assert.NoError(t, err)
+
+	// an address with balances, one emptied after sending, one never used
+	balances := fakeBalanceClient{accounts: map[string]*ctypes.BalanceAccount{
+		addrs[1].Address.String(): {Balances: []ctypes.TokenBalance{{Symbol: "BNB", Free: 1}}},
+		addrs[4].Address.String(): {Sequence: 3},
+		addrs[6].Address.String(): {Number: 12},
+	}}
+	used, err := wallet.ScanUsedAddresses(0, 3, UsedByBalance(balances))
+	assert.NoError(t, err)
+	assert.Equal(t, []DerivedAddress{addrs[1], addrs[4]}, used)
+
+	accounts := fakeAccountClient{
+		addrs[1].Address.String(): &ctypes.AppAccount{BaseAccount: ctypes.BaseAccount{Coins: ctypes.Coins{{Denom: "BNB", Amount: 1}}}},
+		addrs[4].Address.String(): &ctypes.AppAccount{BaseAccount: ctypes.BaseAccount{Sequence: 3}},
+		addrs[6].Address.String(): &ctypes.AppAccount{},
+	}
+	used, err = wallet.ScanUsedAddresses(0, 3, UsedByAccount(accounts))
+	assert.NoError(t, err)
+	assert.Equal(t, []DerivedAddress{addrs[1], addrs[4]}, used)
+
+	_, err = wallet.ScanUsedAddresses(0, 3, UsedByBalance(fakeBalanceClient{err: errors.New("node is down")}))
+	assert.Error(t, err)
+}
+
+func TestMnemonicPassphraseAndLengths(t *testing.T) {
+	// BIP39 reference vector
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	seed, err := MnemonicToSeed(mnemonic, "TREZOR", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	plain, err := NewMnemonicKeyManagerWithOptions(mnemonic)
+	assert.NoError(t, err)
+	withPassphrase, err := NewMnemonicKeyManagerWithOptions(mnemonic, WithPassphrase("TREZOR"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, plain.GetAddr().String(), withPassphrase.GetAddr().String())
+	legacy, err := NewMnemonicKeyManager(mnemonic)
+	assert.NoError(t, err)
+	assert.Equal(t, legacy.GetAddr().String(), plain.GetAddr().String())
+
+	for _, bits := range []int{128, 160, 192, 224, 256} {
+		km, err := NewKeyManagerWithOptions(WithEntropyBits(bits))
+		assert.NoError(t, err)
+		words, err := km.ExportAsMnemonic()
+		assert.NoError(t, err)
+		assert.Equal(t, (bits+bits/32)/11, len(strings.Fields(words)))
+		recovered, err := NewMnemonicKeyManager(words)
+		assert.NoError(t, err)
+		assert.Equal(t, km.GetAddr().String(), recovered.GetAddr().String())
+	}
+
+	_, err = NewMnemonicKeyManager("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")
+	assert.EqualError(t, err, "invalid mnemonic checksum, please check the words and their order")
+	_, err = NewMnemonicKeyManager("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abouts")
+	assert.EqualError(t, err, `word 12 of the mnemonic ("abouts") is not in the wordlist`)
+	_, err = NewKeyManagerWithOptions(WithEntropyBits(100))
+	assert.Error(t, err)
+}
+
+func TestMnemonicCustomWordlist(t *testing.T) {
+	words := make([]string, 2048)
+	for i := range words {
+		words[i] = fmt.Sprintf("w%d", i)
+	}
+	wordlist, err := NewWordlist(words, "")
+	assert.NoError(t, err)
+	km, err := NewKeyManagerWithOptions(WithWordlist(wordlist), WithEntropyBits(128))
+	assert.NoError(t, err)
+	mnemonic, err := km.ExportAsMnemonic()
+	assert.NoError(t, err)
+	recovered, err := NewMnemonicKeyManagerWithOptions(mnemonic, WithWordlist(wordlist))
+	assert.NoError(t, err)
+	assert.Equal(t, km.GetAddr().String(), recovered.GetAddr().String())
+	_, err = NewMnemonicKeyManager(mnemonic)
+	assert.Error(t, err)
+
+	words[1] = words[0]
+	_, err = NewWordlist(words, "")
+	assert.Error(t, err)
+}
+
+func TestMnemonicStandardWordlists(t *testing.T) {
+	// CRC32 of the files of https://github.com/bitcoin/bips/tree/master/bip-0039
+	for words, checksum := range map[string]uint32{
+		chineseSimplifiedWords:  0xe3721bbf,
+		chineseTraditionalWords: 0x3c20b443,
+		czechWords:              0xd1b5fda0,
+		frenchWords:             0x3e56b216,
+		italianWords:            0x2fc7d07e,
+		japaneseWords:           0x0acc1419,
+		koreanWords:             0x4ef461eb,
+		spanishWords:            0x266e4f3d,
+	} {
+		assert.Equal(t, checksum, crc32.ChecksumIEEE([]byte(words)))
+	}
+
+	mnemonic, err := NewMnemonicFromEntropy(make([]byte, 16), JapaneseWordlist)
+	assert.NoError(t, err)
+	// the word list files are NFKD normalized
+	assert.Equal(t, strings.Repeat("あいこくしん\u3000", 11)+"あおぞら", norm.NFC.String(mnemonic))
+
+	for _, wordlist := range []*Wordlist{ChineseSimplifiedWordlist, ChineseTraditionalWordlist, CzechWordlist,
+		FrenchWordlist, ItalianWordlist, JapaneseWordlist, KoreanWordlist, SpanishWordlist} {
+		km, err := NewKeyManagerWithOptions(WithWordlist(wordlist))
+		assert.NoError(t, err)
+		mnemonic, err := km.ExportAsMnemonic()
+		assert.NoError(t, err)
+		recovered, err := NewMnemonicKeyManagerWithOptions(mnemonic, WithWordlist(wordlist))
+		assert.NoError(t, err)
+		assert.Equal(t, km.GetAddr().String(), recovered.GetAddr().String())
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyring, err := NewKeyring(dir, WithKeyringKeyStoreOptions(WithScrypt(LightScryptN, ScryptR, LightScryptP)))
+	assert.NoError(t, err)
+
+	km, err := NewKeyManager()
+	assert.NoError(t, err)
+	info, err := keyring.Add("alice", "password1", km)
+	assert.NoError(t, err)
+	assert.Equal(t, km.GetAddr().String(), info.Address)
+	_, err = keyring.Add("alice", "password1", km)
+	assert.Equal(t, ErrKeyExists, err)
+	_, err = keyring.Add("../alice", "password1", km)
+	assert.Error(t, err)
+
+	keyJSON, err := km.ExportAsKeyStore("password2")
+	assert.NoError(t, err)
+	bz, err := json.Marshal(keyJSON)
+	assert.NoError(t, err)
+	_, err = keyring.ImportKeyStore("bob", bz, "wrong")
+	assert.Equal(t, ErrDecrypt, err)
+	_, err = keyring.ImportKeyStore("bob", bz, "password2")
+	assert.NoError(t, err)
+
+	infos, err := keyring.List()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(infos))
+	assert.Equal(t, "alice", infos[0].Name)
+	assert.Equal(t, "bob", infos[1].Name)
+
+	assert.NoError(t, keyring.Rename("bob", "carol"))
+	_, err = keyring.Get("bob")
+	assert.Equal(t, ErrKeyNotFound, err)
+	assert.NoError(t, keyring.ChangePassword("carol", "password2", "password3"))
+	_, err = keyring.KeyManager("carol", "password2")
+	assert.Equal(t, ErrDecrypt, err)
+	carol, err := keyring.KeyManager("carol", "password3")
+	assert.NoError(t, err)
+	assert.Equal(t, km.GetAddr().String(), carol.GetAddr().String())
+
+	// every local key is a plain keystore file
+	fromFile, err := NewKeyStoreKeyManager(filepath.Join(dir, "alice.json"), "password1")
+	assert.NoError(t, err)
+	assert.Equal(t, km.GetAddr().String(), fromFile.GetAddr().String())
+
+	assert.Error(t, keyring.Delete("alice", "wrong"))
+	assert.NoError(t, keyring.Delete("alice", "password1"))
+	infos, err = keyring.List()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(infos))
+}
+
+func TestRemoteKeyManager(t *testing.T) {
+	km, err := NewMnemonicKeyManager("bottom quick strong ranch section decide pepper broken oven demand coin run jacket curious business achieve mule bamboo remain vote kid rigid bench rubber")
+	assert.NoError(t, err)
+	signer := NewSignerServer(WithSignerToken("secret"), WithSignApprover(func(keyName string, signBytes []byte) error {
+		if bytes.Contains(signBytes, []byte("forbidden")) {
+			return fmt.Errorf("memo is forbidden")
+		}
+		return nil
+	}))
+	signer.AddKey("alice", km)
+	server := httptest.NewServer(signer)
+	defer server.Close()
+
+	_, err = NewRemoteKeyManager(server.URL, "alice", WithRemoteSignerToken("wrong"))
+	assert.Error(t, err)
+	_, err = NewRemoteKeyManager(server.URL, "bob", WithRemoteSignerToken("secret"))
+	assert.Error(t, err)
+	remote, err := NewRemoteKeyManager(server.URL, "alice", WithRemoteSignerToken("secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, km.GetAddr().String(), remote.GetAddr().String())
+
+	signMsg := tx.StdSignMsg{
+		ChainID:       "bnbchain-1000",
+		AccountNumber: 1,
+		Sequence:      2,
+		Msgs:          []msg.Msg{msg.NewMsgSubmitProposal("title", "description", msg.ProposalTypeText, km.GetAddr(), ctypes.Coins{}, time.Hour)},
+		Source:        tx.Source,
+	}
+	expected, err := km.Sign(signMsg)
+	assert.NoError(t, err)
+	signed, err := remote.Sign(signMsg)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(signed))
+
+	signMsg.Memo = "forbidden"
+	_, err = remote.Sign(signMsg)
+	assert.Error(t, err)
+	_, err = remote.ExportAsPrivateKey()
+	assert.Error(t, err)
+}
+
+func TestPolicyKeyManager(t *testing.T) {
+	km, err := NewKeyManager()
+	assert.NoError(t, err)
+	friend, err := NewKeyManager()
+	assert.NoError(t, err)
+	stranger, err := NewKeyManager()
+	assert.NoError(t, err)
+
+	now := time.Date(2019, 7, 1, 10, 0, 0, 0, time.UTC)
+	var records []SignAuditRecord
+	policy := SignPolicy{
+		AllowedMsgTypes:   []string{"send", msg.RouteNewOrder, "tokenIssue"},
+		DailySendLimits:   map[string]int64{"BNB": 100},
+		AllowedRecipients: []string{friend.GetAddr().String()},
+		MaxOrderNotional:  1000e8,
+	}.DenyIssueAndMint()
+	guarded := NewPolicyKeyManager(km, policy,
+		WithSignAudit(func(record SignAuditRecord) { records = append(records, record) }),
+		WithPolicyClock(func() time.Time { return now }))
+
+	send := func(to ctypes.AccAddress, amount int64) error {
+		coins := ctypes.Coins{{Denom: "BNB", Amount: amount}}
+		sendMsg := msg.CreateSendMsg(km.GetAddr(), coins, []msg.Transfer{{ToAddr: to, Coins: coins}})
+		_, err := guarded.Sign(tx.StdSignMsg{ChainID: "bnbchain-1000", Msgs: []msg.Msg{sendMsg}})
+		return err
+	}
+	assert.NoError(t, send(friend.GetAddr(), 60))
+	assert.Error(t, send(stranger.GetAddr(), 1))
+	assert.Error(t, send(friend.GetAddr(), 50))
+	assert.NoError(t, send(friend.GetAddr(), 40))
+	now = now.Add(24 * time.Hour)
+	assert.NoError(t, send(friend.GetAddr(), 100))
+
+	order := msg.NewCreateOrderMsg(km.GetAddr(), "", msg.OrderSide.BUY, "XYZ-000_BNB", 1e8, 1001e8)
+	_, err = guarded.Sign(tx.StdSignMsg{Msgs: []msg.Msg{order}})
+	assert.Error(t, err)
+	order = msg.NewCreateOrderMsg(km.GetAddr(), "", msg.OrderSide.BUY, "XYZ-000_BNB", 1e8, 1000e8)
+	_, err = guarded.Sign(tx.StdSignMsg{Msgs: []msg.Msg{order}})
+	assert.NoError(t, err)
+
+	issue := msg.NewTokenIssueMsg(km.GetAddr(), "New", "NEW", 1e8, false)
+	_, err = guarded.Sign(tx.StdSignMsg{Msgs: []msg.Msg{issue}})
+	assert.Error(t, err)
+	_, err = guarded.Sign(tx.StdSignMsg{Msgs: []msg.Msg{msg.NewCancelOrderMsg(km.GetAddr(), "XYZ-000_BNB", "id")}})
+	assert.Error(t, err)
+
+	assert.Equal(t, 9, len(records))
+	assert.True(t, records[0].Allowed)
+	assert.False(t, records[1].Allowed)
+	assert.NotEmpty(t, records[1].Reason)
+
+	_, err = guarded.ExportAsPrivateKey()
+	assert.Error(t, err)
+	_, err = guarded.GetPrivKey().Sign([]byte("raw"))
+	assert.Error(t, err)
+	assert.True(t, guarded.GetPrivKey().PubKey().Equals(km.GetPrivKey().PubKey()))
+}
+
+func TestLedgerEmulatorKeyManager(t *testing.T) {
+	mnemonic := "bottom quick strong ranch section decide pepper broken oven demand coin run jacket curious business achieve mule bamboo remain vote kid rigid bench rubber"
+	emulator, err := NewLedgerEmulator(mnemonic)
+	assert.NoError(t, err)
+	restore := emulator.Install()
+	defer restore()
+
+	softKm, err := NewMnemonicPathKeyManager(mnemonic, "1'/0/2")
+	assert.NoError(t, err)
+	ledgerKm, err := NewLedgerKeyManager(NewBinanceBIP44Params(1, 2).DerivationPath())
+	assert.NoError(t, err)
+	assert.Equal(t, softKm.GetAddr().String(), ledgerKm.GetAddr().String())
+
+	signMsg := tx.StdSignMsg{
+		ChainID:       "bnbchain-1000",
+		AccountNumber: 1,
+		Sequence:      2,
+		Msgs:          []msg.Msg{msg.NewMsgSubmitProposal("title", "description", msg.ProposalTypeText, softKm.GetAddr(), ctypes.Coins{}, time.Hour)},
+		Source:        tx.Source,
+	}
+	expected, err := softKm.Sign(signMsg)
+	assert.NoError(t, err)
+	signed, err := ledgerKm.Sign(signMsg)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(signed))
+	assert.Equal(t, []string{ledgerKm.GetAddr().String()}, emulator.ShownAddresses())
+
+	dir, err := ioutil.TempDir("", "keyring")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyring, err := NewKeyring(dir)
+	assert.NoError(t, err)
+	info, err := keyring.AddLedger("device", NewBinanceBIP44Params(1, 2).DerivationPath())
+	assert.NoError(t, err)
+	assert.Equal(t, LedgerKey, info.Type)
+	fromKeyring, err := keyring.KeyManager("device", "")
+	assert.NoError(t, err)
+	assert.Equal(t, ledgerKm.GetAddr().String(), fromKeyring.GetAddr().String())
+	_, err = keyring.ExportKeyStore("device")
+	assert.Error(t, err)
+
+	emulator.SetRejectSign(true)
+	_, err = ledgerKm.Sign(signMsg)
+	assert.Equal(t, ErrLedgerSignRejected, err)
+	_, err = ledgerKm.ExportAsPrivateKey()
+	assert.Error(t, err)
+}
+
+func TestLedgerSession(t *testing.T) {
+	mnemonic := "bottom quick strong ranch section decide pepper broken oven demand coin run jacket curious business achieve mule bamboo remain vote kid rigid bench rubber"
+	emulator, err := NewLedgerEmulator(mnemonic)
+	assert.NoError(t, err)
+	restore := emulator.Install()
+	defer restore()
+	wallet, err := NewHDWallet(mnemonic)
+	assert.NoError(t, err)
+
+	session, err := OpenLedgerSession()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.3", session.Version().String())
+	accounts, err := session.Accounts(0, 0, 3, func(addr ctypes.AccAddress) ([]ctypes.TokenBalance, error) {
+		return []ctypes.TokenBalance{{Symbol: "BNB"}}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(accounts))
+	for _, acc := range accounts {
+		addr, err := wallet.Address(acc.Account, acc.Index)
+		assert.NoError(t, err)
+		assert.Equal(t, addr.String(), acc.Address.String())
+		assert.Equal(t, "BNB", acc.Balances[0].Symbol)
+	}
+	assert.NoError(t, session.ConfirmAddress(accounts[2]))
+	km, err := session.KeyManager(accounts[2], true)
+	assert.NoError(t, err)
+	assert.Equal(t, accounts[2].Address.String(), km.GetAddr().String())
+	assert.Equal(t, []string{km.GetAddr().String(), km.GetAddr().String()}, emulator.ShownAddresses())
+
+	emulator.SetLocked(true)
+	_, err = session.Accounts(0, 0, 1, nil)
+	assert.True(t, errors.Is(err, ErrLedgerLocked))
+	emulator.SetLocked(false)
+	assert.NoError(t, session.Close())
+	_, err = session.KeyManager(accounts[0], false)
+	assert.True(t, errors.Is(err, ErrLedgerNotConnected))
+
+	emulator.SetVersion(ledgergo.VersionInfo{Major: 1, Minor: 0, Patch: 9})
+	_, err = OpenLedgerSession()
+	assert.True(t, errors.Is(err, ErrLedgerAppVersion))
+}
+
+func TestChainKeyManager(t *testing.T) {
+	km, err := NewKeyManager()
+	assert.NoError(t, err)
+	network := ctypes.Network
+	defer func() { ctypes.Network = network }()
+
+	ctypes.Network = ctypes.TestNetwork
+	guarded := NewChainKeyManager(km, ctypes.TestNetwork, "Binance-Chain-Nile")
+	sendMsg := msg.CreateSendMsg(km.GetAddr(), ctypes.Coins{{Denom: "BNB", Amount: 1}},
+		[]msg.Transfer{{ToAddr: km.GetAddr(), Coins: ctypes.Coins{{Denom: "BNB", Amount: 1}}}})
+	_, err = guarded.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Nile", Msgs: []msg.Msg{sendMsg}})
+	assert.NoError(t, err)
+	_, err = guarded.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Tigris", Msgs: []msg.Msg{sendMsg}})
+	assert.True(t, errors.Is(err, ErrChainIDNotAllowed))
+
+	// the raw signatures of the signer server are checked too
+	privKey := guarded.GetPrivKey()
+	assert.True(t, privKey.PubKey().Equals(km.GetPrivKey().PubKey()))
+	_, err = privKey.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Nile", Msgs: []msg.Msg{sendMsg}}.Bytes())
+	assert.NoError(t, err)
+	_, err = privKey.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Tigris", Msgs: []msg.Msg{sendMsg}}.Bytes())
+	assert.True(t, errors.Is(err, ErrChainIDNotAllowed))
+	_, err = privKey.Sign([]byte("not a sign message"))
+	assert.Error(t, err)
+
+	signer := NewSignerServer(WithSignerToken("secret"))
+	signer.AddKey("alice", guarded)
+	server := httptest.NewServer(signer)
+	defer server.Close()
+	remote, err := NewRemoteKeyManager(server.URL, "alice", WithRemoteSignerToken("secret"))
+	assert.NoError(t, err)
+	expected, err := km.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Nile", Msgs: []msg.Msg{sendMsg}})
+	assert.NoError(t, err)
+	signed, err := remote.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Nile", Msgs: []msg.Msg{sendMsg}})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, signed)
+	_, err = remote.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Tigris", Msgs: []msg.Msg{sendMsg}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not allowed")
+	}
+
+	ctypes.Network = ctypes.ProdNetwork
+	_, err = guarded.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Nile", Msgs: []msg.Msg{sendMsg}})
+	assert.True(t, errors.Is(err, ErrNetworkMismatch))
+	_, err = privKey.Sign(tx.StdSignMsg{ChainID: "Binance-Chain-Nile", Msgs: []msg.Msg{sendMsg}}.Bytes())
+	assert.True(t, errors.Is(err, ErrNetworkMismatch))
+}
+
+func TestChainKeyManagerExport(t *testing.T) {
+	km, err := NewKeyManager()
+	assert.NoError(t, err)
+	guarded := NewChainKeyManager(km, ctypes.TestNetwork, "Binance-Chain-Nile")
+	assert.Nil(t, guarded.GetPrivKey().Bytes())
+	_, err = guarded.ExportAsMnemonic()
+	assert.Error(t, err)
+	_, err = guarded.ExportAsPrivateKey()
+	assert.Error(t, err)
+	_, err = guarded.ExportAsKeyStore("testpassword")
+	assert.Error(t, err)
+}
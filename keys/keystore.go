@@ -9,13 +9,101 @@ import (
 	"errors"
 	"fmt"
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
 	"golang.org/x/crypto/sha3"
+	"math"
+)
+
+const (
+	KDFPBKDF2 = "pbkdf2"
+	KDFScrypt = "scrypt"
+
+	// DefaultPBKDF2C is the iteration count of the keystores generated by default.
+	DefaultPBKDF2C = 262144
+
+	// StandardScryptN and StandardScryptP are the scrypt parameters of the
+	// keystores generated by most wallets, they use 256MB of memory.
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+	// LightScryptN and LightScryptP use 4MB of memory, for constrained devices.
+	LightScryptN = 1 << 12
+	LightScryptP = 6
+	ScryptR      = 8
+
+	keyStoreDKLen = 32
+
+	// cipherAES128CTR is the cipher of the Web3 Secret Storage keystores
+	// written by Ethereum wallets, the keystores of this SDK use AES-256.
+	cipherAES128CTR = "aes-128-ctr"
 )
 
 var (
 	ErrDecrypt = errors.New("could not decrypt key with given passphrase")
 )
 
+type keyStoreConfig struct {
+	kdf string
+	c   int
+	n   int
+	r   int
+	p   int
+}
+
+type KeyStoreOption func(*keyStoreConfig)
+
+// WithPBKDF2 derives the keystore encryption key with PBKDF2-HMAC-SHA256 and c
+// iterations. It is the default, with DefaultPBKDF2C iterations.
+func WithPBKDF2(c int) KeyStoreOption {
+	return func(cfg *keyStoreConfig) {
+		cfg.kdf = KDFPBKDF2
+		cfg.c = c
+	}
+}
+
+// WithScrypt derives the keystore encryption key with scrypt.
+func WithScrypt(n, r, p int) KeyStoreOption {
+	return func(cfg *keyStoreConfig) {
+		cfg.kdf = KDFScrypt
+		cfg.n = n
+		cfg.r = r
+		cfg.p = p
+	}
+}
+
+func (cfg *keyStoreConfig) kdfParams(salt []byte) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, 5)
+	params["dklen"] = keyStoreDKLen
+	params["salt"] = hex.EncodeToString(salt)
+	switch cfg.kdf {
+	case KDFPBKDF2:
+		if cfg.c <= 0 {
+			return nil, fmt.Errorf("PBKDF2 iteration count should be positive")
+		}
+		params["prf"] = "hmac-sha256"
+		params["c"] = cfg.c
+	case KDFScrypt:
+		if err := validateScryptParams(cfg.n, cfg.r, cfg.p); err != nil {
+			return nil, err
+		}
+		params["n"] = cfg.n
+		params["r"] = cfg.r
+		params["p"] = cfg.p
+	default:
+		return nil, fmt.Errorf("Unsupported KDF: %s", cfg.kdf)
+	}
+	return params, nil
+}
+
+func validateScryptParams(n, r, p int) error {
+	if n <= 1 || n&(n-1) != 0 {
+		return fmt.Errorf("scrypt N should be a power of 2 greater than 1, got %d", n)
+	}
+	if r <= 0 || p <= 0 || uint64(r)*uint64(p) >= 1<<30 {
+		return fmt.Errorf("invalid scrypt parameters r=%d p=%d", r, p)
+	}
+	return nil
+}
+
 type PlainKeyJSON struct {
 	Address    string `json:"address"`
 	PrivateKey string `json:"privatekey"`
@@ -62,6 +150,17 @@ func decryptKey(keyProtected *EncryptedKeyJSON, auth string) ([]byte, error) {
 		return nil, err
 	}
 
+	if keyProtected.Crypto.Cipher == cipherAES128CTR {
+		// Web3 Secret Storage: keccak256 MAC and the first half of the derived key
+		hasher := sha3.NewLegacyKeccak256()
+		hasher.Write(derivedKey[16:32])
+		hasher.Write(cipherText)
+		if !bytes.Equal(hasher.Sum(nil), mac) {
+			return nil, ErrDecrypt
+		}
+		return aesCTRXOR(derivedKey[:16], cipherText, iv)
+	}
+
 	bufferValue := make([]byte, len(cipherText)+16)
 	copy(bufferValue[0:16], derivedKey[16:32])
 	copy(bufferValue[16:], cipherText[:])
@@ -88,30 +187,88 @@ func decryptKey(keyProtected *EncryptedKeyJSON, auth string) ([]byte, error) {
 
 func getKDFKey(cryptoJSON CryptoJSON, auth string) ([]byte, error) {
 	authArray := []byte(auth)
-	salt, err := hex.DecodeString(cryptoJSON.KDFParams["salt"].(string))
+	params := cryptoJSON.KDFParams
+	saltHex, err := kdfString(params, "salt")
+	if err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(saltHex)
 	if err != nil {
 		return nil, err
 	}
-	dkLen := ensureInt(cryptoJSON.KDFParams["dklen"])
+	dkLen, err := kdfInt(params, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	if dkLen < keyStoreDKLen {
+		return nil, fmt.Errorf("KDF dklen should be at least %d, got %d", keyStoreDKLen, dkLen)
+	}
 
-	if cryptoJSON.KDF == "pbkdf2" {
-		c := ensureInt(cryptoJSON.KDFParams["c"])
-		prf := cryptoJSON.KDFParams["prf"].(string)
+	switch cryptoJSON.KDF {
+	case KDFPBKDF2:
+		c, err := kdfInt(params, "c")
+		if err != nil {
+			return nil, err
+		}
+		prf, err := kdfString(params, "prf")
+		if err != nil {
+			return nil, err
+		}
 		if prf != "hmac-sha256" {
 			return nil, fmt.Errorf("Unsupported PBKDF2 PRF: %s", prf)
 		}
 		key := pbkdf2.Key(authArray, salt, c, dkLen, sha256.New)
 		return key, nil
+	case KDFScrypt:
+		n, err := kdfInt(params, "n")
+		if err != nil {
+			return nil, err
+		}
+		r, err := kdfInt(params, "r")
+		if err != nil {
+			return nil, err
+		}
+		p, err := kdfInt(params, "p")
+		if err != nil {
+			return nil, err
+		}
+		if err := validateScryptParams(n, r, p); err != nil {
+			return nil, err
+		}
+		return scrypt.Key(authArray, salt, n, r, p, dkLen)
 	}
 	return nil, fmt.Errorf("Unsupported KDF: %s", cryptoJSON.KDF)
 }
 
-func ensureInt(x interface{}) int {
-	res, ok := x.(int)
+// kdfInt returns the integer KDF parameter name, the keystore file may omit it
+// or hold anything there.
+func kdfInt(params map[string]interface{}, name string) (int, error) {
+	res, err := ensureInt(params[name])
+	if err != nil {
+		return 0, fmt.Errorf("invalid KDF param %s: %s", name, err.Error())
+	}
+	return res, nil
+}
+
+func kdfString(params map[string]interface{}, name string) (string, error) {
+	res, ok := params[name].(string)
 	if !ok {
-		res = int(x.(float64))
+		return "", fmt.Errorf("invalid KDF param %s: expected a string, got %v", name, params[name])
+	}
+	return res, nil
+}
+
+func ensureInt(x interface{}) (int, error) {
+	switch v := x.(type) {
+	case int:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt32 {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int(v), nil
 	}
-	return res
+	return 0, fmt.Errorf("expected an integer, got %v", x)
 }
 
 func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
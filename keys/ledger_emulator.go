@@ -0,0 +1,183 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	ledgergo "github.com/binance-chain/ledger-cosmos-go"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/binance-chain/go-sdk/common/bech32"
+	"github.com/binance-chain/go-sdk/common/ledger"
+)
+
+var (
+	ErrLedgerEmulatorClosed = errors.New("ledger emulator is closed")
+
+	// the message of the device when the screen is locked
+	errLedgerEmulatorLocked = errors.New("[APDU_CODE_EMPTY_BUFFER] Security condition not satisfied")
+)
+
+// LedgerEmulator is a software implementation of ledger.LedgerSecp256k1 whose
+// keys are derived from a mnemonic, like the device does from its seed. It lets
+// the Ledger code paths run without a device, in tests and in CI.
+type LedgerEmulator struct {
+	mtx        sync.Mutex
+	masterPriv [32]byte
+	chainCode  [32]byte
+	version    ledgergo.VersionInfo
+	reject     bool
+	locked     bool
+	closed     bool
+	shown      []string
+}
+
+var _ ledger.LedgerSecp256k1 = (*LedgerEmulator)(nil)
+
+func NewLedgerEmulator(mnemonic string, options ...MnemonicOption) (*LedgerEmulator, error) {
+	o := newMnemonicOptions(options...)
+	seed, err := MnemonicToSeed(mnemonic, o.passphrase, o.wordlist)
+	if err != nil {
+		return nil, err
+	}
+	masterPriv, ch := ComputeMastersFromSeed(seed)
+	return &LedgerEmulator{
+		masterPriv: masterPriv,
+		chainCode:  ch,
+		version:    ledgergo.VersionInfo{AppMode: 0, Major: 1, Minor: 1, Patch: 3},
+	}, nil
+}
+
+// Install makes ledger.DiscoverLedger return the emulator, and returns the
+// function restoring the previous discovery function.
+func (e *LedgerEmulator) Install() (restore func()) {
+	previous := ledger.DiscoverLedger
+	ledger.DiscoverLedger = func() (ledger.LedgerSecp256k1, error) {
+		e.mtx.Lock()
+		defer e.mtx.Unlock()
+		e.closed = false
+		return e, nil
+	}
+	return func() {
+		ledger.DiscoverLedger = previous
+	}
+}
+
+// SetVersion sets the app version reported by GetVersion.
+func (e *LedgerEmulator) SetVersion(version ledgergo.VersionInfo) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.version = version
+}
+
+// SetRejectSign makes the emulator behave as if the user rejected every signature.
+func (e *LedgerEmulator) SetRejectSign(reject bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.reject = reject
+}
+
+// SetLocked makes the emulator behave as a locked device.
+func (e *LedgerEmulator) SetLocked(locked bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.locked = locked
+}
+
+// ShownAddresses returns the addresses displayed by ShowAddressSECP256K1, in order.
+func (e *LedgerEmulator) ShownAddresses() []string {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return append([]string{}, e.shown...)
+}
+
+func (e *LedgerEmulator) GetPublicKeySECP256K1(path []uint32) ([]byte, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	priv, err := e.derive(path)
+	if err != nil {
+		return nil, err
+	}
+	// the device returns the uncompressed public key
+	return priv.PubKey().SerializeUncompressed(), nil
+}
+
+func (e *LedgerEmulator) ShowAddressSECP256K1(path []uint32, hrp string) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	priv, err := e.derive(path)
+	if err != nil {
+		return err
+	}
+	var pubKey secp256k1.PubKeySecp256k1
+	copy(pubKey[:], priv.PubKey().SerializeCompressed())
+	addr, err := bech32.ConvertAndEncode(hrp, pubKey.Address())
+	if err != nil {
+		return err
+	}
+	e.shown = append(e.shown, addr)
+	return nil
+}
+
+func (e *LedgerEmulator) SignSECP256K1(path []uint32, msg []byte) ([]byte, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	priv, err := e.derive(path)
+	if err != nil {
+		return nil, err
+	}
+	if e.reject {
+		return nil, ErrLedgerSignRejected
+	}
+	hash := sha256.Sum256(msg)
+	sig, err := priv.Sign(hash[:])
+	if err != nil {
+		return nil, err
+	}
+	// the device returns DER signatures
+	return sig.Serialize(), nil
+}
+
+func (e *LedgerEmulator) GetVersion() (*ledgergo.VersionInfo, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.closed {
+		return nil, ErrLedgerEmulatorClosed
+	}
+	if e.locked {
+		return nil, errLedgerEmulatorLocked
+	}
+	version := e.version
+	return &version, nil
+}
+
+func (e *LedgerEmulator) Close() error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.closed = true
+	return nil
+}
+
+// derive follows the device conventions: the path has 5 levels, and the first
+// 3 ones are hardened.
+func (e *LedgerEmulator) derive(path []uint32) (*btcec.PrivateKey, error) {
+	if e.closed {
+		return nil, ErrLedgerEmulatorClosed
+	}
+	if e.locked {
+		return nil, errLedgerEmulatorLocked
+	}
+	if len(path) != 5 {
+		return nil, fmt.Errorf("ledger derivation path should have 5 levels, got %d", len(path))
+	}
+	pathStr := fmt.Sprintf("%d'/%d'/%d'/%d/%d", path[0], path[1], path[2], path[3], path[4])
+	derived, err := DerivePrivateKeyForPath(e.masterPriv, e.chainCode, pathStr)
+	if err != nil {
+		return nil, err
+	}
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), derived[:])
+	return priv, nil
+}
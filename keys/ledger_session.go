@@ -0,0 +1,147 @@
+package keys
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	ledgergo "github.com/binance-chain/ledger-cosmos-go"
+
+	"github.com/binance-chain/go-sdk/common/ledger"
+	ctypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+// Errors of the Ledger session, test them with errors.Is.
+var (
+	ErrLedgerNotConnected = errors.New("ledger device is not connected")
+	ErrLedgerLocked       = errors.New("ledger device is locked or the Binance app is not open")
+	ErrLedgerAppVersion   = errors.New("ledger app version is not supported")
+	ErrLedgerSignRejected = errors.New("signature rejected on ledger")
+)
+
+// MinLedgerAppVersion is the oldest app able to show addresses on the device.
+var MinLedgerAppVersion = ledgergo.VersionInfo{Major: 1, Minor: 1, Patch: 0}
+
+// LedgerAccount is an address of the device together with its derivation path.
+type LedgerAccount struct {
+	Account  uint32                `json:"account"`
+	Index    uint32                `json:"index"`
+	Path     ledger.DerivationPath `json:"path"`
+	Address  ctypes.AccAddress     `json:"address"`
+	Balances []ctypes.TokenBalance `json:"balances,omitempty"`
+}
+
+// LedgerBalanceFunc returns the balances of an address, usually from GetAccount.
+type LedgerBalanceFunc func(addr ctypes.AccAddress) ([]ctypes.TokenBalance, error)
+
+// LedgerSession is an open connection to a Ledger device. It lists the
+// addresses of the device, confirms them on screen and returns the
+// KeyManager of the picked one.
+type LedgerSession struct {
+	device  ledger.LedgerSecp256k1
+	version ledgergo.VersionInfo
+}
+
+// OpenLedgerSession connects to the Ledger device and checks that its app
+// version is at least MinLedgerAppVersion.
+func OpenLedgerSession() (*LedgerSession, error) {
+	if ledger.DiscoverLedger == nil {
+		return nil, fmt.Errorf("no Ledger discovery function defined, please make sure you have added ledger to build tags and cgo is enabled")
+	}
+	device, err := ledger.DiscoverLedger()
+	if err != nil {
+		return nil, classifyLedgerError(err)
+	}
+	version, err := device.GetVersion()
+	if err != nil {
+		device.Close()
+		return nil, classifyLedgerError(err)
+	}
+	if !ledgergo.CheckVersion(*version, MinLedgerAppVersion) {
+		device.Close()
+		return nil, fmt.Errorf("%w: got %s, required %s", ErrLedgerAppVersion, version.String(), MinLedgerAppVersion.String())
+	}
+	return &LedgerSession{device: device, version: *version}, nil
+}
+
+// Version returns the version of the app running on the device.
+func (s *LedgerSession) Version() ledgergo.VersionInfo {
+	return s.version
+}
+
+// Accounts lists count addresses of account, starting at address index from.
+// The balances are filled in when balances is not nil.
+func (s *LedgerSession) Accounts(account, from, count uint32, balances LedgerBalanceFunc) ([]LedgerAccount, error) {
+	accounts := make([]LedgerAccount, 0, count)
+	for index := from; index < from+count; index++ {
+		path := ledger.DerivationPath(NewBinanceBIP44Params(account, index).DerivationPath())
+		pkl, err := ledger.GenLedgerSecp256k1Key(path, s.device)
+		if err != nil {
+			return nil, classifyLedgerError(err)
+		}
+		acc := LedgerAccount{Account: account, Index: index, Path: path, Address: ctypes.AccAddress(pkl.PubKey().Address())}
+		if balances != nil {
+			if acc.Balances, err = balances(acc.Address); err != nil {
+				return nil, fmt.Errorf("failed to get balances of %s: %s", acc.Address.String(), err.Error())
+			}
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+// ConfirmAddress shows the address of acc on the device for the user to compare.
+func (s *LedgerSession) ConfirmAddress(acc LedgerAccount) error {
+	return classifyLedgerError(s.device.ShowAddressSECP256K1(acc.Path, ctypes.Network.Bech32Prefixes()))
+}
+
+// KeyManager returns the KeyManager of acc, showing its address on the device
+// first when confirm is true.
+func (s *LedgerSession) KeyManager(acc LedgerAccount, confirm bool) (KeyManager, error) {
+	pkl, err := ledger.GenLedgerSecp256k1Key(acc.Path, s.device)
+	if err != nil {
+		return nil, classifyLedgerError(err)
+	}
+	addr := ctypes.AccAddress(pkl.PubKey().Address())
+	if acc.Address != nil && !bytes.Equal(addr, acc.Address) {
+		return nil, fmt.Errorf("the device holds %s at this path instead of %s", addr.String(), acc.Address.String())
+	}
+	if confirm {
+		if err := pkl.ShowSignAddr(); err != nil {
+			return nil, classifyLedgerError(err)
+		}
+	}
+	return &keyManager{privKey: pkl, addr: addr}, nil
+}
+
+func (s *LedgerSession) Close() error {
+	return s.device.Close()
+}
+
+// classifyLedgerError maps the errors of the device to the session errors.
+func classifyLedgerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var kind error
+	msg := err.Error()
+	switch {
+	case err == ErrLedgerEmulatorClosed, strings.Contains(msg, "no ledger connected"),
+		strings.Contains(msg, "hidapi"), strings.Contains(msg, "device not found"):
+		kind = ErrLedgerNotConnected
+	case strings.Contains(msg, "Security condition not satisfied"), strings.Contains(msg, "Class not supported"),
+		strings.Contains(msg, "app is open"):
+		kind = ErrLedgerLocked
+	case err == ErrLedgerSignRejected, strings.Contains(msg, "Conditions of use not satisfied"):
+		kind = ErrLedgerSignRejected
+	case strings.Contains(msg, "version not supported"):
+		kind = ErrLedgerAppVersion
+	default:
+		return err
+	}
+	if err == kind {
+		return err
+	}
+	return fmt.Errorf("%w: %s", kind, msg)
+}
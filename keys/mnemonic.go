@@ -0,0 +1,240 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/cosmos/go-bip39"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	bip39WordlistSize  = 2048
+	bip39SeedRounds    = 2048
+	bip39SeedLength    = 64
+	defaultEntropyBits = 256
+)
+
+// EnglishWordlist is the default BIP39 word list.
+var EnglishWordlist = mustNewWordlist(bip39.EnglishWordList, " ")
+
+// Wordlist is a BIP39 word list. The lists published with BIP39 are provided,
+// such as JapaneseWordlist or FrenchWordlist, NewWordlist loads any other one.
+type Wordlist struct {
+	words     []string
+	index     map[string]int
+	separator string
+}
+
+// NewWordlist builds a word list from its 2048 words. separator is used to join the
+// words of the generated mnemonics, it defaults to a space. Japanese mnemonics
+// should use the ideographic space "　".
+func NewWordlist(words []string, separator string) (*Wordlist, error) {
+	if len(words) != bip39WordlistSize {
+		return nil, fmt.Errorf("a BIP39 wordlist should have %d words, got %d", bip39WordlistSize, len(words))
+	}
+	if separator == "" {
+		separator = " "
+	}
+	w := &Wordlist{
+		words:     make([]string, len(words)),
+		index:     make(map[string]int, len(words)),
+		separator: separator,
+	}
+	for i, word := range words {
+		// mnemonics are generated with the words as given, and looked up
+		// once normalized
+		word = strings.TrimSpace(word)
+		key := norm.NFKD.String(word)
+		if key == "" {
+			return nil, fmt.Errorf("word %d of the wordlist is empty", i)
+		}
+		if _, ok := w.index[key]; ok {
+			return nil, fmt.Errorf("word %q appears twice in the wordlist", word)
+		}
+		w.words[i] = word
+		w.index[key] = i
+	}
+	return w, nil
+}
+
+// splitWords splits a word list file, one word per line.
+func splitWords(list string) []string {
+	return strings.Split(strings.TrimSpace(list), "\n")
+}
+
+func mustNewWordlist(words []string, separator string) *Wordlist {
+	w, err := NewWordlist(words, separator)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+type mnemonicOptions struct {
+	passphrase  string
+	wordlist    *Wordlist
+	entropyBits int
+	keyPath     string
+}
+
+type MnemonicOption func(*mnemonicOptions)
+
+// WithPassphrase sets the optional BIP39 passphrase, also known as the 25th word.
+func WithPassphrase(passphrase string) MnemonicOption {
+	return func(o *mnemonicOptions) {
+		o.passphrase = passphrase
+	}
+}
+
+// WithWordlist sets the word list of the mnemonic, English by default.
+func WithWordlist(wordlist *Wordlist) MnemonicOption {
+	return func(o *mnemonicOptions) {
+		o.wordlist = wordlist
+	}
+}
+
+// WithEntropyBits sets the entropy of a generated mnemonic: 128, 160, 192, 224 or 256 bits
+// give 12, 15, 18, 21 or 24 words. It defaults to 256.
+func WithEntropyBits(bits int) MnemonicOption {
+	return func(o *mnemonicOptions) {
+		o.entropyBits = bits
+	}
+}
+
+// WithKeyPath sets the "account' / change / address_index" part of the BIP44 path,
+// the "purpose' / coin_type'" part is fixed as "44'/714'/".
+func WithKeyPath(keyPath string) MnemonicOption {
+	return func(o *mnemonicOptions) {
+		o.keyPath = keyPath
+	}
+}
+
+func newMnemonicOptions(options ...MnemonicOption) *mnemonicOptions {
+	o := &mnemonicOptions{
+		passphrase:  defaultBIP39Passphrase,
+		wordlist:    EnglishWordlist,
+		entropyBits: defaultEntropyBits,
+		keyPath:     PartialPath,
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// NewKeyManagerWithOptions generates a new mnemonic and returns its KeyManager.
+func NewKeyManagerWithOptions(options ...MnemonicOption) (KeyManager, error) {
+	o := newMnemonicOptions(options...)
+	entropy, err := bip39.NewEntropy(o.entropyBits)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic, err := NewMnemonicFromEntropy(entropy, o.wordlist)
+	if err != nil {
+		return nil, err
+	}
+	return NewMnemonicKeyManagerWithOptions(mnemonic, options...)
+}
+
+// NewMnemonicKeyManagerWithOptions recovers a KeyManager from a mnemonic created with
+// a passphrase, a non-English word list or a custom path.
+func NewMnemonicKeyManagerWithOptions(mnemonic string, options ...MnemonicOption) (KeyManager, error) {
+	o := newMnemonicOptions(options...)
+	seed, err := MnemonicToSeed(mnemonic, o.passphrase, o.wordlist)
+	if err != nil {
+		return nil, err
+	}
+	masterPriv, ch := ComputeMastersFromSeed(seed)
+	k := keyManager{}
+	if err := k.recoveryFromMasterKey(masterPriv, ch, BIP44Prefix+o.keyPath); err != nil {
+		return nil, err
+	}
+	k.mnemonic = mnemonic
+	return &k, nil
+}
+
+// NewMnemonicFromEntropy encodes entropy as a mnemonic of the word list.
+func NewMnemonicFromEntropy(entropy []byte, wordlist *Wordlist) (string, error) {
+	if wordlist == nil {
+		wordlist = EnglishWordlist
+	}
+	entropyBits := len(entropy) * 8
+	if err := validateEntropyBits(entropyBits); err != nil {
+		return "", err
+	}
+	checksumBits := entropyBits / 32
+	wordsNum := (entropyBits + checksumBits) / 11
+
+	hash := sha256.Sum256(entropy)
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-uint(checksumBits)))))
+
+	words := make([]string, wordsNum)
+	mask := big.NewInt(bip39WordlistSize - 1)
+	for i := wordsNum - 1; i >= 0; i-- {
+		words[i] = wordlist.words[new(big.Int).And(data, mask).Int64()]
+		data.Rsh(data, 11)
+	}
+	return strings.Join(words, wordlist.separator), nil
+}
+
+// MnemonicToEntropy decodes a mnemonic of the word list and verifies its checksum.
+func MnemonicToEntropy(mnemonic string, wordlist *Wordlist) ([]byte, error) {
+	if wordlist == nil {
+		wordlist = EnglishWordlist
+	}
+	words := mnemonicWords(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, fmt.Errorf("mnemonic should have 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+	data := new(big.Int)
+	for i, word := range words {
+		idx, ok := wordlist.index[word]
+		if !ok {
+			return nil, fmt.Errorf("word %d of the mnemonic (%q) is not in the wordlist", i+1, word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(idx)))
+	}
+	checksumBits := len(words) * 11 / 33
+	entropyBits := checksumBits * 32
+	checksum := new(big.Int).And(data, big.NewInt(1<<uint(checksumBits)-1)).Int64()
+	data.Rsh(data, uint(checksumBits))
+
+	entropy := make([]byte, entropyBits/8)
+	bz := data.Bytes()
+	copy(entropy[len(entropy)-len(bz):], bz)
+	hash := sha256.Sum256(entropy)
+	if int64(hash[0]>>(8-uint(checksumBits))) != checksum {
+		return nil, fmt.Errorf("invalid mnemonic checksum, please check the words and their order")
+	}
+	return entropy, nil
+}
+
+// MnemonicToSeed verifies the mnemonic and derives the BIP39 seed with the passphrase.
+func MnemonicToSeed(mnemonic, passphrase string, wordlist *Wordlist) ([]byte, error) {
+	if _, err := MnemonicToEntropy(mnemonic, wordlist); err != nil {
+		return nil, err
+	}
+	sentence := strings.Join(mnemonicWords(mnemonic), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(sentence), []byte(salt), bip39SeedRounds, bip39SeedLength, sha512.New), nil
+}
+
+func mnemonicWords(mnemonic string) []string {
+	// NFKD turns the ideographic spaces of Japanese mnemonics into regular spaces.
+	return strings.Fields(norm.NFKD.String(mnemonic))
+}
+
+func validateEntropyBits(bits int) error {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return fmt.Errorf("entropy should be 128, 160, 192, 224 or 256 bits, got %d", bits)
+	}
+	return nil
+}
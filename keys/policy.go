@@ -0,0 +1,259 @@
+package keys
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+
+	ctypes "github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/msg"
+	"github.com/binance-chain/go-sdk/types/tx"
+)
+
+// SignPolicy lists the rules a transaction must follow to be signed by a
+// policy KeyManager. Zero values mean no restriction.
+type SignPolicy struct {
+	// AllowedMsgTypes are the msg.Msg Type() values that may be signed, such as "send" or "orderNew".
+	AllowedMsgTypes []string
+	// DeniedMsgTypes are never signed, they take precedence over AllowedMsgTypes.
+	DeniedMsgTypes []string
+	// DailySendLimits bounds, per denom, the coins sent by SendMsg within a UTC day.
+	DailySendLimits map[string]int64
+	// AllowedRecipients are the only addresses SendMsg may pay, besides the signer itself.
+	AllowedRecipients []string
+	// MaxOrderNotional bounds price * quantity of a new order, in 1e8 units of the quote asset.
+	MaxOrderNotional int64
+}
+
+// DenyIssueAndMint returns a copy of the policy refusing to issue and mint tokens.
+func (p SignPolicy) DenyIssueAndMint() SignPolicy {
+	p.DeniedMsgTypes = append(append([]string{}, p.DeniedMsgTypes...), msg.TokenIssueMsg{}.Type(), msg.MintMsg{}.Type())
+	return p
+}
+
+// SignAuditRecord describes a signature request and the policy decision.
+type SignAuditRecord struct {
+	Time     time.Time `json:"time"`
+	Address  string    `json:"address"`
+	ChainID  string    `json:"chain_id"`
+	Sequence int64     `json:"sequence"`
+	MsgTypes []string  `json:"msg_types"`
+	Memo     string    `json:"memo,omitempty"`
+	Allowed  bool      `json:"allowed"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// SignAuditFunc receives a record for every signature request, allowed or refused.
+type SignAuditFunc func(record SignAuditRecord)
+
+type policyKeyManager struct {
+	KeyManager
+	policy     SignPolicy
+	audit      SignAuditFunc
+	now        func() time.Time
+	recipients map[string]bool
+
+	mtx      sync.Mutex
+	spentDay string
+	spent    ctypes.Coins
+}
+
+type PolicyOption func(*policyKeyManager)
+
+// WithSignAudit sets the function receiving the audit records.
+func WithSignAudit(audit SignAuditFunc) PolicyOption {
+	return func(m *policyKeyManager) {
+		m.audit = audit
+	}
+}
+
+// WithPolicyClock sets the clock used for the daily limits, time.Now by default.
+func WithPolicyClock(now func() time.Time) PolicyOption {
+	return func(m *policyKeyManager) {
+		m.now = now
+	}
+}
+
+// NewPolicyKeyManager wraps km so that only the transactions following policy
+// are signed. The private key of km can't be reached through the wrapper: its
+// exports are refused and the PrivKey it returns does not sign.
+func NewPolicyKeyManager(km KeyManager, policy SignPolicy, options ...PolicyOption) KeyManager {
+	m := &policyKeyManager{KeyManager: km, policy: policy, now: time.Now}
+	for _, option := range options {
+		option(m)
+	}
+	if len(policy.AllowedRecipients) > 0 {
+		m.recipients = make(map[string]bool, len(policy.AllowedRecipients))
+		for _, addr := range policy.AllowedRecipients {
+			m.recipients[addr] = true
+		}
+	}
+	return m
+}
+
+func (m *policyKeyManager) Sign(signMsg tx.StdSignMsg) ([]byte, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	record := SignAuditRecord{
+		Time:     m.now().UTC(),
+		Address:  m.GetAddr().String(),
+		ChainID:  signMsg.ChainID,
+		Sequence: signMsg.Sequence,
+		Memo:     signMsg.Memo,
+	}
+	for _, sm := range signMsg.Msgs {
+		record.MsgTypes = append(record.MsgTypes, sm.Type())
+	}
+	day := record.Time.Format("2006-01-02")
+	if day != m.spentDay {
+		m.spentDay = day
+		m.spent = nil
+	}
+
+	sent, err := m.check(signMsg)
+	if err == nil {
+		var bz []byte
+		if bz, err = m.KeyManager.Sign(signMsg); err == nil {
+			m.spent = m.spent.Plus(sent)
+			record.Allowed = true
+			m.auditRecord(record)
+			return bz, nil
+		}
+	}
+	record.Reason = err.Error()
+	m.auditRecord(record)
+	return nil, err
+}
+
+// check returns the coins the message sends away when it follows the policy.
+func (m *policyKeyManager) check(signMsg tx.StdSignMsg) (ctypes.Coins, error) {
+	sent := ctypes.Coins{}
+	for _, sm := range signMsg.Msgs {
+		if err := m.checkType(sm.Type()); err != nil {
+			return nil, err
+		}
+		switch sm := sm.(type) {
+		case msg.SendMsg:
+			coins, err := m.checkSend(sm)
+			if err != nil {
+				return nil, err
+			}
+			sent = sent.Plus(coins)
+		case *msg.SendMsg:
+			coins, err := m.checkSend(*sm)
+			if err != nil {
+				return nil, err
+			}
+			sent = sent.Plus(coins)
+		case msg.CreateOrderMsg:
+			if err := m.checkOrder(sm); err != nil {
+				return nil, err
+			}
+		case *msg.CreateOrderMsg:
+			if err := m.checkOrder(*sm); err != nil {
+				return nil, err
+			}
+		}
+	}
+	total := m.spent.Plus(sent)
+	for denom, limit := range m.policy.DailySendLimits {
+		if amount := total.AmountOf(denom); amount > limit {
+			return nil, fmt.Errorf("daily send limit of %d %s exceeded: %d would be sent today", limit, denom, amount)
+		}
+	}
+	return sent, nil
+}
+
+func (m *policyKeyManager) checkType(msgType string) error {
+	for _, denied := range m.policy.DeniedMsgTypes {
+		if msgType == denied {
+			return fmt.Errorf("msg type %s is denied by the sign policy", msgType)
+		}
+	}
+	if len(m.policy.AllowedMsgTypes) == 0 {
+		return nil
+	}
+	for _, allowed := range m.policy.AllowedMsgTypes {
+		if msgType == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("msg type %s is not allowed by the sign policy", msgType)
+}
+
+func (m *policyKeyManager) checkSend(sendMsg msg.SendMsg) (ctypes.Coins, error) {
+	self := m.GetAddr().String()
+	sent := ctypes.Coins{}
+	for _, output := range sendMsg.Outputs {
+		to := output.Address.String()
+		if to == self {
+			continue
+		}
+		if m.recipients != nil && !m.recipients[to] {
+			return nil, fmt.Errorf("recipient %s is not allowed by the sign policy", to)
+		}
+		sent = sent.Plus(output.Coins)
+	}
+	return sent, nil
+}
+
+func (m *policyKeyManager) checkOrder(order msg.CreateOrderMsg) error {
+	if m.policy.MaxOrderNotional <= 0 {
+		return nil
+	}
+	notional := new(big.Int).Mul(big.NewInt(order.Price), big.NewInt(order.Quantity))
+	notional.Div(notional, big.NewInt(1e8))
+	if notional.Cmp(big.NewInt(m.policy.MaxOrderNotional)) > 0 {
+		return fmt.Errorf("order notional %s of %s exceeds the maximum %d allowed by the sign policy", notional.String(), order.Symbol, m.policy.MaxOrderNotional)
+	}
+	return nil
+}
+
+func (m *policyKeyManager) auditRecord(record SignAuditRecord) {
+	if m.audit != nil {
+		m.audit(record)
+	}
+}
+
+func (m *policyKeyManager) GetPrivKey() crypto.PrivKey {
+	return policyPrivKey{m.KeyManager.GetPrivKey()}
+}
+
+func (m *policyKeyManager) ExportAsMnemonic() (string, error) {
+	return "", fmt.Errorf("export is disabled by the sign policy")
+}
+
+func (m *policyKeyManager) ExportAsPrivateKey() (string, error) {
+	return "", fmt.Errorf("export is disabled by the sign policy")
+}
+
+func (m *policyKeyManager) ExportAsKeyStore(password string, options ...KeyStoreOption) (*EncryptedKeyJSON, error) {
+	return nil, fmt.Errorf("export is disabled by the sign policy")
+}
+
+// policyPrivKey exposes the public key only, raw signatures would bypass the policy.
+type policyPrivKey struct {
+	privKey crypto.PrivKey
+}
+
+func (pk policyPrivKey) Bytes() []byte {
+	return nil
+}
+
+func (pk policyPrivKey) Sign(msg []byte) ([]byte, error) {
+	return nil, fmt.Errorf("raw signatures are disabled by the sign policy")
+}
+
+func (pk policyPrivKey) PubKey() crypto.PubKey {
+	return pk.privKey.PubKey()
+}
+
+func (pk policyPrivKey) Equals(other crypto.PrivKey) bool {
+	if o, ok := other.(policyPrivKey); ok {
+		return pk.privKey.Equals(o.privKey)
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package keys
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"gopkg.in/resty.v1"
+
+	ctypes "github.com/binance-chain/go-sdk/common/types"
+)
+
+const (
+	RemoteSignerPubKeyPath = "/pubkey"
+	RemoteSignerSignPath   = "/sign"
+
+	defaultRemoteSignerTimeout = 30 * time.Second
+)
+
+// RemotePubKeyResponse is the answer of a remote signer to a public key request.
+type RemotePubKeyResponse struct {
+	Address string `json:"address"`
+	PubKey  string `json:"pub_key"` // hex of the 33 bytes compressed secp256k1 public key
+}
+
+// RemoteSignRequest asks a remote signer to sign the bytes of a StdSignMsg.
+type RemoteSignRequest struct {
+	Key       string `json:"key"`
+	SignBytes string `json:"sign_bytes"` // hex
+}
+
+// RemoteSignResponse holds the signature of a RemoteSignRequest.
+type RemoteSignResponse struct {
+	Signature string `json:"signature"` // hex
+}
+
+type remoteSignerError struct {
+	Error string `json:"error"`
+}
+
+type RemoteSignerOption func(*resty.Client)
+
+// WithRemoteSignerToken authenticates with a bearer token.
+func WithRemoteSignerToken(token string) RemoteSignerOption {
+	return func(c *resty.Client) {
+		c.SetAuthToken(token)
+	}
+}
+
+// WithRemoteSignerTLS sets the TLS configuration, including the client
+// certificate when the signer requires mutual TLS.
+func WithRemoteSignerTLS(config *tls.Config) RemoteSignerOption {
+	return func(c *resty.Client) {
+		c.SetTLSClientConfig(config)
+	}
+}
+
+// WithRemoteSignerTimeout bounds the time of every request to the signer.
+func WithRemoteSignerTimeout(timeout time.Duration) RemoteSignerOption {
+	return func(c *resty.Client) {
+		c.SetTimeout(timeout)
+	}
+}
+
+// PrivKeyRemoteSecp256k1 implements PrivKey, delegating the signatures to a
+// remote signer. The private key never leaves the signer.
+type PrivKeyRemoteSecp256k1 struct {
+	client  *resty.Client
+	keyName string
+	pubkey  secp256k1.PubKeySecp256k1
+}
+
+// NewRemoteKeyManager returns a KeyManager signing with the key keyName of the
+// remote signer at endpoint, such as "https://signer.internal:8443".
+func NewRemoteKeyManager(endpoint, keyName string, options ...RemoteSignerOption) (KeyManager, error) {
+	c := resty.New().SetHostURL(endpoint).SetTimeout(defaultRemoteSignerTimeout)
+	for _, option := range options {
+		option(c)
+	}
+	var pubKeyResp RemotePubKeyResponse
+	if err := remoteSignerCall(c.R().SetQueryParam("key", keyName), http.MethodGet, RemoteSignerPubKeyPath, &pubKeyResp); err != nil {
+		return nil, err
+	}
+	bz, err := hex.DecodeString(pubKeyResp.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) != secp256k1.PubKeySecp256k1Size {
+		return nil, fmt.Errorf("remote signer returned a public key of %d bytes", len(bz))
+	}
+	pkr := PrivKeyRemoteSecp256k1{client: c, keyName: keyName}
+	copy(pkr.pubkey[:], bz)
+	addr := ctypes.AccAddress(pkr.pubkey.Address())
+	if pubKeyResp.Address != "" && pubKeyResp.Address != addr.String() {
+		return nil, fmt.Errorf("remote signer address %s does not match its public key", pubKeyResp.Address)
+	}
+	return &keyManager{privKey: pkr, addr: addr}, nil
+}
+
+func (pkr PrivKeyRemoteSecp256k1) Bytes() []byte {
+	return nil
+}
+
+func (pkr PrivKeyRemoteSecp256k1) Sign(msg []byte) ([]byte, error) {
+	req := RemoteSignRequest{Key: pkr.keyName, SignBytes: hex.EncodeToString(msg)}
+	var resp RemoteSignResponse
+	if err := remoteSignerCall(pkr.client.R().SetBody(req), http.MethodPost, RemoteSignerSignPath, &resp); err != nil {
+		return nil, err
+	}
+	sig, err := hex.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !pkr.pubkey.VerifyBytes(msg, sig) {
+		return nil, fmt.Errorf("remote signer returned an invalid signature")
+	}
+	return sig, nil
+}
+
+func (pkr PrivKeyRemoteSecp256k1) PubKey() crypto.PubKey {
+	return pkr.pubkey
+}
+
+func (pkr PrivKeyRemoteSecp256k1) Equals(other crypto.PrivKey) bool {
+	if remote, ok := other.(PrivKeyRemoteSecp256k1); ok {
+		return pkr.PubKey().Equals(remote.PubKey())
+	}
+	return false
+}
+
+func remoteSignerCall(req *resty.Request, method, path string, result interface{}) error {
+	resp, err := req.Execute(method, path)
+	if err != nil {
+		return fmt.Errorf("failed to reach remote signer: %s", err.Error())
+	}
+	if resp.StatusCode() != http.StatusOK {
+		var signerErr remoteSignerError
+		if json.Unmarshal(resp.Body(), &signerErr) == nil && signerErr.Error != "" {
+			return fmt.Errorf("remote signer error, status code %d: %s", resp.StatusCode(), signerErr.Error)
+		}
+		return fmt.Errorf("bad response, status code %d, response: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	return json.Unmarshal(resp.Body(), result)
+}
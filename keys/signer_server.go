@@ -0,0 +1,167 @@
+package keys
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+const maxSignRequestBytes = 1 << 20
+
+// SignApprover is called before every remote signature, returning an error refuses it.
+type SignApprover func(keyName string, signBytes []byte) error
+
+// SignerServer is a reference remote signer, serving the keys it holds over
+// HTTP to NewRemoteKeyManager. Serve it with http.Server and a TLS config whose
+// ClientAuth is tls.RequireAndVerifyClientCert to require mutual TLS.
+type SignerServer struct {
+	mtx      sync.RWMutex
+	keys     map[string]KeyManager
+	token    string
+	approver SignApprover
+	mux      *http.ServeMux
+}
+
+type SignerServerOption func(*SignerServer)
+
+// WithSignerToken requires clients to present the bearer token.
+func WithSignerToken(token string) SignerServerOption {
+	return func(s *SignerServer) {
+		s.token = token
+	}
+}
+
+// WithSignApprover sets a hook allowed to refuse signatures.
+func WithSignApprover(approver SignApprover) SignerServerOption {
+	return func(s *SignerServer) {
+		s.approver = approver
+	}
+}
+
+func NewSignerServer(options ...SignerServerOption) *SignerServer {
+	s := &SignerServer{keys: make(map[string]KeyManager), mux: http.NewServeMux()}
+	for _, option := range options {
+		option(s)
+	}
+	s.mux.HandleFunc(RemoteSignerPubKeyPath, s.handlePubKey)
+	s.mux.HandleFunc(RemoteSignerSignPath, s.handleSign)
+	return s
+}
+
+// AddKey serves km under name.
+func (s *SignerServer) AddKey(name string, km KeyManager) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.keys[name] = km
+}
+
+// AddKeyringKey unlocks the key name of the keyring and serves it under the same name.
+func (s *SignerServer) AddKeyringKey(keyring *Keyring, name, password string) error {
+	km, err := keyring.KeyManager(name, password)
+	if err != nil {
+		return err
+	}
+	s.AddKey(name, km)
+	return nil
+}
+
+func (s *SignerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeSignerError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *SignerServer) key(name string) (KeyManager, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	km, ok := s.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", name)
+	}
+	return km, nil
+}
+
+func (s *SignerServer) handlePubKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeSignerError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	km, err := s.key(r.URL.Query().Get("key"))
+	if err != nil {
+		writeSignerError(w, http.StatusNotFound, err)
+		return
+	}
+	pubKey, ok := km.GetPrivKey().PubKey().(secp256k1.PubKeySecp256k1)
+	if !ok {
+		writeSignerError(w, http.StatusInternalServerError, fmt.Errorf("only secp256k1 keys are supported"))
+		return
+	}
+	writeSignerResponse(w, RemotePubKeyResponse{Address: km.GetAddr().String(), PubKey: hex.EncodeToString(pubKey[:])})
+}
+
+func (s *SignerServer) handleSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeSignerError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSignRequestBytes))
+	if err != nil {
+		writeSignerError(w, http.StatusBadRequest, err)
+		return
+	}
+	var req RemoteSignRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeSignerError(w, http.StatusBadRequest, err)
+		return
+	}
+	km, err := s.key(req.Key)
+	if err != nil {
+		writeSignerError(w, http.StatusNotFound, err)
+		return
+	}
+	signBytes, err := hex.DecodeString(req.SignBytes)
+	if err != nil {
+		writeSignerError(w, http.StatusBadRequest, err)
+		return
+	}
+	// StdSignMsg.Bytes() is a JSON document, refuse to sign anything else
+	if !json.Valid(signBytes) {
+		writeSignerError(w, http.StatusBadRequest, fmt.Errorf("sign bytes are not a sign message"))
+		return
+	}
+	if s.approver != nil {
+		if err := s.approver(req.Key, signBytes); err != nil {
+			writeSignerError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+	sig, err := km.GetPrivKey().Sign(signBytes)
+	if err != nil {
+		writeSignerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeSignerResponse(w, RemoteSignResponse{Signature: hex.EncodeToString(sig)})
+}
+
+func writeSignerResponse(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeSignerError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(remoteSignerError{Error: err.Error()})
+}
@@ -0,0 +1,24 @@
+package tx
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// TxHash returns the hash of the amino encoded signed transaction txBytes, in
+// the upper case hex form the chain reports.
+func TxHash(txBytes []byte) string {
+	return strings.ToUpper(hex.EncodeToString(tmhash.Sum(txBytes)))
+}
+
+// HexTxHash returns the hash of a hex encoded signed transaction, as returned
+// by KeyManager.Sign.
+func HexTxHash(hexTx []byte) (string, error) {
+	txBytes, err := hex.DecodeString(string(hexTx))
+	if err != nil {
+		return "", err
+	}
+	return TxHash(txBytes), nil
+}
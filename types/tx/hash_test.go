@@ -0,0 +1,22 @@
+package tx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxHash(t *testing.T) {
+	// the SHA-256 of "abc", the hash the chain reports for these tx bytes
+	const expected = "BA7816BF8F01CFEA414140DE5DAE2223B00361A396177A9CB410FF61F20015AD"
+	assert.Equal(t, expected, TxHash([]byte("abc")))
+
+	hash, err := HexTxHash([]byte("616263"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hash)
+	hash, err = HexTxHash([]byte("616263"[:5]))
+	assert.Error(t, err)
+	assert.Empty(t, hash)
+	_, err = HexTxHash([]byte("not hex"))
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package tx
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/binance-chain/go-sdk/types/msg"
 	"github.com/tendermint/tendermint/crypto"
@@ -62,3 +63,14 @@ func StdSignBytes(chainID string, accnum int64, sequence int64, msgs []msg.Msg,
 	}
 	return msg.MustSortJSON(bz)
 }
+
+// MaxMemoCharacters is the max length of the memo accepted by the chain.
+const MaxMemoCharacters = 128
+
+// ValidateMemo checks the memo against the chain limits before it gets signed.
+func ValidateMemo(memo string) error {
+	if len(memo) > MaxMemoCharacters {
+		return fmt.Errorf("memo is too long, got %d characters, max is %d", len(memo), MaxMemoCharacters)
+	}
+	return nil
+}